@@ -29,7 +29,7 @@ func deposit() {
 		CustomerZipCode:     "84280",
 		CustomerPhone:       "+1 420-100-1000",
 		CustomerIP:          "127.0.0.1",
-		CustomerBankCode:    "BBL",
+		CustomerBankCode:    "MBB",
 		RedirectURL:         "https://some.endpoint/redirect",
 		CallbackURL:         "https://some.endpoint/callback",
 		CheckoutURL:         "https://some.endpoint/checkout",
@@ -90,7 +90,7 @@ func depositMocked() {
 		CustomerZipCode:     "84280",
 		CustomerPhone:       "+1 420-100-1000",
 		CustomerIP:          "127.0.0.1",
-		CustomerBankCode:    "BBL",
+		CustomerBankCode:    "MBB",
 		RedirectURL:         "https://some.endpoint/redirect",
 		CallbackURL:         "https://some.endpoint/callback",
 		CheckoutURL:         "https://some.endpoint/checkout",
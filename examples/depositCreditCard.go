@@ -38,9 +38,9 @@ func depositCC() {
 
 		// credit card data
 		CardHolderName:      "TEST TEST",
-		CardNumber:          "4222222222347466",
+		CardNumber:          "4222222222222220",
 		CardExpirationMonth: "01",
-		CardExpirationYear:  "21",
+		CardExpirationYear:  "30",
 		CardCvv:             "111",
 	})
 
@@ -24,7 +24,7 @@ func payout() {
 		CustomerLastName:               "Doe",
 		CustomerPhone:                  "+1 420-100-1000",
 		CustomerIP:                     "127.0.0.1",
-		CustomerBankCode:               "BBL",
+		CustomerBankCode:               "MBB",
 		CallbackURL:                    "https://some.endpoint/callback",
 		CheckoutURL:                    "https://some.endpoint/checkout",
 		CustomerBankAccountNumber:      "100200",
@@ -91,7 +91,7 @@ func payoutMocked() {
 		CustomerLastName:               "Doe",
 		CustomerPhone:                  "+1 420-100-1000",
 		CustomerIP:                     "127.0.0.1",
-		CustomerBankCode:               "BBL",
+		CustomerBankCode:               "MBB",
 		CallbackURL:                    "https://some.endpoint/callback",
 		CheckoutURL:                    "https://some.endpoint/checkout",
 		CustomerBankAccountNumber:      "100200",
@@ -0,0 +1,27 @@
+package zota
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "store.json")
+
+	assert.Equal(t, nil, writeFileAtomic(path, []byte(`{"a":1}`)))
+	assert.Equal(t, nil, writeFileAtomic(path, []byte(`{}`)))
+	b, err := os.ReadFile(path)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{}`, string(b))
+
+	//the temp file is renamed
+	_, err = os.Stat(path + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+
+	//a missing directory is an error
+	err = writeFileAtomic(filepath.Join(t.TempDir(), "missing", "store.json"), []byte(`{}`))
+	assert.NotEqual(t, nil, err)
+}
@@ -0,0 +1,148 @@
+package zota
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Bank represents a bank of the BankCatalog
+// Code is the value expected in CustomerBankCode
+type Bank struct {
+	Code       string   `json:"code"`
+	Name       string   `json:"name"`
+	Country    string   `json:"country"`
+	Currencies []string `json:"currencies"`
+	Deposit    bool     `json:"deposit"`
+	Payout     bool     `json:"payout"`
+}
+
+// BankCatalog is a versioned list of the bank codes supported per currency
+type BankCatalog struct {
+	Version string `json:"version"`
+	Banks   []Bank `json:"banks"`
+}
+
+// BankQuery represents the filters of BankCatalog.Search
+// empty fields are not used for filtering
+type BankQuery struct {
+	Currency string
+	Country  string
+	// Text is matched against the code and the name of the bank
+	Text    string
+	Deposit bool
+	Payout  bool
+}
+
+//go:embed bankCatalog.json
+var bankCatalogJSON []byte
+
+// DefaultBankCatalog is the bank catalog embedded in the SDK
+// used when SDK.BankCatalog is nil
+var DefaultBankCatalog = mustParseBankCatalog(bankCatalogJSON)
+
+// ParseBankCatalog parses a json encoded BankCatalog
+func ParseBankCatalog(b []byte) (*BankCatalog, error) {
+	var c BankCatalog
+	err := json.Unmarshal(b, &c)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected bank catalog json:%v", err)
+	}
+	for i, bank := range c.Banks {
+		if bank.Code == "" {
+			return nil, fmt.Errorf("bank %v has no code", i)
+		}
+		if len(bank.Currencies) == 0 {
+			return nil, fmt.Errorf("bank %v has no currencies", bank.Code)
+		}
+	}
+	return &c, nil
+}
+
+// LoadBankCatalog reads a json encoded BankCatalog from a file
+// to be used in SDK.BankCatalog instead of DefaultBankCatalog
+func LoadBankCatalog(path string) (*BankCatalog, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseBankCatalog(b)
+}
+
+// mustParseBankCatalog parses the embedded catalog and panics on error
+func mustParseBankCatalog(b []byte) *BankCatalog {
+	c, err := ParseBankCatalog(b)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Lookup returns the bank with the code for the currency
+func (c *BankCatalog) Lookup(currency string, code string) (Bank, bool) {
+	for _, bank := range c.Banks {
+		if strings.EqualFold(bank.Code, code) && containsFold(bank.Currencies, currency) {
+			return bank, true
+		}
+	}
+	return Bank{}, false
+}
+
+// Search returns the banks matching all the filters of the query
+func (c *BankCatalog) Search(q BankQuery) []Bank {
+	var banks []Bank
+	text := strings.ToLower(q.Text)
+	for _, bank := range c.Banks {
+		if q.Currency != "" && !containsFold(bank.Currencies, q.Currency) {
+			continue
+		}
+		if q.Country != "" && !strings.EqualFold(bank.Country, q.Country) {
+			continue
+		}
+		if text != "" && !strings.Contains(strings.ToLower(bank.Code), text) && !strings.Contains(strings.ToLower(bank.Name), text) {
+			continue
+		}
+		if (q.Deposit && !bank.Deposit) || (q.Payout && !bank.Payout) {
+			continue
+		}
+		banks = append(banks, bank)
+	}
+	return banks
+}
+
+// HasCurrency reports whether the catalog contains banks for the currency
+func (c *BankCatalog) HasCurrency(currency string) bool {
+	for _, bank := range c.Banks {
+		if containsFold(bank.Currencies, currency) {
+			return true
+		}
+	}
+	return false
+}
+
+// validateBankCode checks that code is a known bank for the currency
+// and that the bank supports the deposit or payout operation
+// empty codes and currencies not present in the catalog are not validated
+func (c *BankCatalog) validateBankCode(currency string, code string, operation Operation) error {
+	if code == "" || !c.HasCurrency(currency) {
+		return nil
+	}
+	bank, ok := c.Lookup(currency, code)
+	if !ok {
+		return FieldErrors{{Field: "CustomerBankCode", Message: fmt.Sprintf("is not a known bank for %v", strings.ToUpper(currency))}}
+	}
+	if (operation == OperationDeposit && !bank.Deposit) || (operation == OperationPayout && !bank.Payout) {
+		return FieldErrors{{Field: "CustomerBankCode", Message: fmt.Sprintf("is not supported for %v", operation)}}
+	}
+	return nil
+}
+
+// bankCatalog returns the catalog used by the SDK
+func (s *SDK) bankCatalog() *BankCatalog {
+	if s.BankCatalog == nil {
+		return DefaultBankCatalog
+	}
+	return s.BankCatalog
+}
@@ -0,0 +1,127 @@
+package zota
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultBankCatalog(t *testing.T) {
+	assert.NotEqual(t, "", DefaultBankCatalog.Version)
+	for _, currency := range []string{"MYR", "THB", "VND", "IDR"} {
+		assert.True(t, DefaultBankCatalog.HasCurrency(currency), currency)
+	}
+
+	bank, ok := DefaultBankCatalog.Lookup("THB", "bbl")
+	assert.True(t, ok)
+	assert.Equal(t, "Bangkok Bank", bank.Name)
+	assert.Equal(t, "TH", bank.Country)
+
+	_, ok = DefaultBankCatalog.Lookup("MYR", "BBL")
+	assert.False(t, ok)
+}
+
+func TestBankCatalog_Search(t *testing.T) {
+	c, err := ParseBankCatalog([]byte(`{"version":"test","banks":[
+		{"code":"AAA","name":"Alpha Bank","country":"MY","currencies":["MYR"],"deposit":true,"payout":false},
+		{"code":"BBB","name":"Beta Bank","country":"MY","currencies":["MYR","USD"],"deposit":true,"payout":true},
+		{"code":"CCC","name":"Gamma","country":"TH","currencies":["THB"],"deposit":false,"payout":true}
+	]}`))
+	assert.Equal(t, nil, err)
+
+	codes := func(banks []Bank) (res []string) {
+		for _, b := range banks {
+			res = append(res, b.Code)
+		}
+		return
+	}
+	assert.Equal(t, []string{"AAA", "BBB"}, codes(c.Search(BankQuery{Currency: "myr"})))
+	assert.Equal(t, []string{"BBB"}, codes(c.Search(BankQuery{Currency: "MYR", Payout: true})))
+	assert.Equal(t, []string{"AAA", "BBB"}, codes(c.Search(BankQuery{Text: "bank"})))
+	assert.Equal(t, []string{"CCC"}, codes(c.Search(BankQuery{Country: "TH", Deposit: false})))
+	assert.Equal(t, []string(nil), codes(c.Search(BankQuery{Country: "TH", Deposit: true})))
+}
+
+func TestParseBankCatalog(t *testing.T) {
+	_, err := ParseBankCatalog([]byte(`{"banks":[{"currencies":["MYR"]}]}`))
+	assert.Equal(t, fmt.Errorf("bank 0 has no code"), err)
+
+	_, err = ParseBankCatalog([]byte(`{"banks":[{"code":"AAA"}]}`))
+	assert.Equal(t, fmt.Errorf("bank AAA has no currencies"), err)
+
+	_, err = ParseBankCatalog([]byte(`not json`))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestLoadBankCatalog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "banks.json")
+	assert.Equal(t, nil, os.WriteFile(path, []byte(`{"version":"custom","banks":[{"code":"XYZ","name":"Custom","country":"MY","currencies":["MYR"],"deposit":true}]}`), 0600))
+
+	c, err := LoadBankCatalog(path)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "custom", c.Version)
+
+	_, err = LoadBankCatalog(filepath.Join(t.TempDir(), "missing.json"))
+	assert.NotEqual(t, nil, err)
+}
+
+func Test_ValidateBankCode(t *testing.T) {
+	c, _ := ParseBankCatalog([]byte(`{"banks":[{"code":"XYZ","currencies":["MYR"],"deposit":true}]}`))
+
+	assert.Equal(t, nil, c.validateBankCode("MYR", "XYZ", OperationDeposit))
+	assert.Equal(t, nil, c.validateBankCode("MYR", "", OperationDeposit))
+	assert.Equal(t, nil, c.validateBankCode("USD", "ANY", OperationDeposit))
+	assert.Equal(t, FieldErrors{{Field: "CustomerBankCode", Message: "is not a known bank for MYR"}}, c.validateBankCode("myr", "BBL", OperationDeposit))
+	assert.Equal(t, FieldErrors{{Field: "CustomerBankCode", Message: "is not supported for payout"}}, c.validateBankCode("MYR", "XYZ", OperationPayout))
+}
+
+func Test_DepositPayoutBankCatalog(t *testing.T) {
+	sdk := SDK{
+		MerchantID:        "API_MERCHANT_ID",
+		MerchantSecretKey: "API_MERCHANT_SECRET_KEY",
+		EndpointID:        "503368",
+		ApiBaseURL:        SANDBOX,
+		HttpClient:        &PClientMockSuccess{},
+	}
+
+	p := PayoutOrder{
+		MerchantOrderID:           "134e4f443t651",
+		MerchantOrderDesc:         "Test order description",
+		OrderAmount:               "500",
+		OrderCurrency:             "MYR",
+		CustomerBankCode:          "BBL",
+		CustomerBankAccountNumber: "100200",
+		CustomerBankAccountName:   "John Doe",
+	}
+	_, err := sdk.Payout(p)
+	assert.Equal(t, FieldErrors{{Field: "CustomerBankCode", Message: "is not a known bank for MYR"}}, err)
+
+	//custom catalog
+	sdk.BankCatalog, _ = ParseBankCatalog([]byte(`{"banks":[{"code":"BBL","currencies":["MYR"],"payout":true}]}`))
+	res, err := sdk.Payout(p)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "200", res.Code)
+
+	sdk.HttpClient = &ClientMockSuccess{}
+	_, err = sdk.Deposit(DepositOrder{
+		MerchantOrderID:     "134",
+		MerchantOrderDesc:   "Test order description",
+		OrderAmount:         "500",
+		OrderCurrency:       "MYR",
+		CustomerEmail:       "customer@email-address.com",
+		CustomerLastName:    "Doe",
+		CustomerAddress:     "The Swan, Jungle St. 108",
+		CustomerCountryCode: "MY",
+		CustomerCity:        "Kuala Lumpur",
+		CustomerZipCode:     "84280",
+		CustomerPhone:       "+60 420-100-1000",
+		CustomerIP:          "127.0.0.1",
+		CustomerBankCode:    "BBL",
+		RedirectURL:         "https://some.endpoint/redirect",
+		CheckoutURL:         "https://some.endpoint/checkout",
+	})
+	assert.Equal(t, FieldErrors{{Field: "CustomerBankCode", Message: "is not supported for deposit"}}, err)
+}
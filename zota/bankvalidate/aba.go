@@ -0,0 +1,18 @@
+package bankvalidate
+
+import (
+	"fmt"
+)
+
+// ABARouting validates a 9 digits US ABA routing number checksum
+func ABARouting(routing string) error {
+	d, ok := digits(routing)
+	if !ok || len(d) != 9 {
+		return fmt.Errorf("ABA routing number must be 9 digits")
+	}
+	sum := 3*(d[0]+d[3]+d[6]) + 7*(d[1]+d[4]+d[7]) + (d[2] + d[5] + d[8])
+	if sum%10 != 0 {
+		return fmt.Errorf("invalid ABA routing number checksum")
+	}
+	return nil
+}
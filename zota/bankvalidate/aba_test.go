@@ -0,0 +1,21 @@
+package bankvalidate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestABARouting(t *testing.T) {
+	tests := map[string]error{
+		"011000015": nil,
+		"021000021": nil,
+		"021000022": fmt.Errorf("invalid ABA routing number checksum"),
+		"02100002":  fmt.Errorf("ABA routing number must be 9 digits"),
+		"02100002a": fmt.Errorf("ABA routing number must be 9 digits"),
+	}
+	for routing, expected := range tests {
+		assert.Equal(t, expected, ABARouting(routing), routing)
+	}
+}
@@ -0,0 +1,26 @@
+// Package bankvalidate provides validators for bank identifiers
+// used in Zota payout orders: IBAN, SWIFT/BIC, ABA routing numbers,
+// Brazilian CPF/CNPJ and Brazilian bank account check digits
+package bankvalidate
+
+import (
+	"strings"
+)
+
+// clean removes the formatting characters
+// commonly used when writing bank identifiers
+func clean(s string) string {
+	return strings.NewReplacer(" ", "", ".", "", "-", "", "/", "").Replace(s)
+}
+
+// digits returns s as a list of ints, false if s contains non digit characters
+func digits(s string) ([]int, bool) {
+	d := make([]int, 0, len(s))
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return nil, false
+		}
+		d = append(d, int(c-'0'))
+	}
+	return d, true
+}
@@ -0,0 +1,29 @@
+package bankvalidate
+
+import (
+	"fmt"
+)
+
+// BIC validates the structure of a SWIFT/BIC code
+// 4 letters bank code, 2 letters country code, 2 alphanumeric location code
+// and an optional 3 alphanumeric branch code
+func BIC(bic string) error {
+	if len(bic) != 8 && len(bic) != 11 {
+		return fmt.Errorf("invalid BIC length")
+	}
+	for i, c := range bic {
+		isLetter := c >= 'A' && c <= 'Z'
+		isDigit := c >= '0' && c <= '9'
+		switch {
+		case i < 4 && !isLetter:
+			return fmt.Errorf("invalid BIC bank code")
+		case i >= 4 && i < 6 && !isLetter:
+			return fmt.Errorf("invalid BIC country code")
+		case i >= 6 && i < 8 && !isLetter && !isDigit:
+			return fmt.Errorf("invalid BIC location code")
+		case i >= 8 && !isLetter && !isDigit:
+			return fmt.Errorf("invalid BIC branch code")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,25 @@
+package bankvalidate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBIC(t *testing.T) {
+	tests := map[string]error{
+		"DEUTDEFF":    nil,
+		"DEUTDEFF500": nil,
+		"NEDSZAJJXXX": nil,
+		"DEUTDEF":     fmt.Errorf("invalid BIC length"),
+		"DEU1DEFF":    fmt.Errorf("invalid BIC bank code"),
+		"DEUTD3FF":    fmt.Errorf("invalid BIC country code"),
+		"DEUTDEF-":    fmt.Errorf("invalid BIC location code"),
+		"DEUTDEFF50_": fmt.Errorf("invalid BIC branch code"),
+		"deutdeff":    fmt.Errorf("invalid BIC bank code"),
+	}
+	for bic, expected := range tests {
+		assert.Equal(t, expected, BIC(bic), bic)
+	}
+}
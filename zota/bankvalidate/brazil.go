@@ -0,0 +1,219 @@
+package bankvalidate
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CPF validates the check digits of a Brazilian individual taxpayer number
+// punctuation ("123.456.789-09") is allowed
+func CPF(cpf string) error {
+	d, ok := digits(clean(cpf))
+	if !ok || len(d) != 11 {
+		return fmt.Errorf("CPF must be 11 digits")
+	}
+	if allEqual(d) {
+		return fmt.Errorf("invalid CPF")
+	}
+	for n := 9; n <= 10; n++ {
+		sum := 0
+		for i := 0; i < n; i++ {
+			sum += d[i] * (n + 1 - i)
+		}
+		dv := sum * 10 % 11
+		if dv == 10 {
+			dv = 0
+		}
+		if dv != d[n] {
+			return fmt.Errorf("invalid CPF check digits")
+		}
+	}
+	return nil
+}
+
+// CNPJ validates the check digits of a Brazilian company taxpayer number
+// punctuation ("11.222.333/0001-81") is allowed
+func CNPJ(cnpj string) error {
+	d, ok := digits(clean(cnpj))
+	if !ok || len(d) != 14 {
+		return fmt.Errorf("CNPJ must be 14 digits")
+	}
+	if allEqual(d) {
+		return fmt.Errorf("invalid CNPJ")
+	}
+	weights := []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+	for n := 12; n <= 13; n++ {
+		sum := 0
+		for i := 0; i < n; i++ {
+			sum += d[i] * weights[i+13-n]
+		}
+		dv := 0
+		if sum%11 >= 2 {
+			dv = 11 - sum%11
+		}
+		if dv != d[n] {
+			return fmt.Errorf("invalid CNPJ check digits")
+		}
+	}
+	return nil
+}
+
+// BrazilPersonalID validates a CPF (11 digits) or a CNPJ (14 digits)
+func BrazilPersonalID(id string) error {
+	if len(clean(id)) == 14 {
+		return CNPJ(id)
+	}
+	return CPF(id)
+}
+
+// BrazilAccountRule describes the check digit rules of a Brazilian bank
+// Branch and Account return the expected check digit,
+// Branch is nil if the bank branches have no check digit
+type BrazilAccountRule struct {
+	Name    string
+	Branch  func(branch string) (string, error)
+	Account func(branch string, account string) (string, error)
+}
+
+// BrazilAccountRules are the known account rules keyed by the 3 digits bank code (COMPE)
+// banks without a rule are not validated
+var BrazilAccountRules = map[string]BrazilAccountRule{
+	"001": {
+		Name:    "Banco do Brasil",
+		Branch:  mod11Rule(4, []int{5, 4, 3, 2}, "X", "0"),
+		Account: ignoreBranch(mod11Rule(8, []int{9, 8, 7, 6, 5, 4, 3, 2}, "X", "0")),
+	},
+	"237": {
+		Name:    "Bradesco",
+		Branch:  mod11Rule(4, []int{5, 4, 3, 2}, "P", "0"),
+		Account: ignoreBranch(mod11Rule(7, []int{2, 7, 6, 5, 4, 3, 2}, "P", "0")),
+	},
+	"341": {
+		Name:    "Itaú",
+		Account: itauAccount,
+	},
+}
+
+// BrazilAccountField is the part of a Brazilian bank account failing the validation
+type BrazilAccountField string
+
+const (
+	BrazilBranch       BrazilAccountField = "branch"
+	BrazilBranchDigit  BrazilAccountField = "branch digit"
+	BrazilAccount      BrazilAccountField = "account"
+	BrazilAccountDigit BrazilAccountField = "account digit"
+)
+
+// BrazilAccountError is the error of BrazilBankAccount
+// Field is the part of the account that failed
+type BrazilAccountError struct {
+	Field   BrazilAccountField
+	Message string
+}
+
+// Error implements error
+func (e *BrazilAccountError) Error() string {
+	return e.Message
+}
+
+// brazilAccountError returns err as a BrazilAccountError of the field
+// unless it already is one, e.g. a branch error of an account rule
+func brazilAccountError(field BrazilAccountField, err error) error {
+	var accountErr *BrazilAccountError
+	if errors.As(err, &accountErr) {
+		return err
+	}
+	return &BrazilAccountError{Field: field, Message: err.Error()}
+}
+
+// BrazilBankAccount validates the branch and account check digits
+// using the BrazilAccountRules of the bank
+// returns nil if there is no rule for the bank, a *BrazilAccountError otherwise
+func BrazilBankAccount(bankCode, branch, branchDigit, account, accountDigit string) error {
+	rule, ok := BrazilAccountRules[bankCode]
+	if !ok {
+		return nil
+	}
+	if rule.Branch != nil {
+		dv, err := rule.Branch(clean(branch))
+		if err != nil {
+			return brazilAccountError(BrazilBranch, err)
+		}
+		if !strings.EqualFold(dv, branchDigit) {
+			return &BrazilAccountError{Field: BrazilBranchDigit, Message: fmt.Sprintf("invalid branch check digit for %v", rule.Name)}
+		}
+	}
+	if rule.Account != nil {
+		dv, err := rule.Account(clean(branch), clean(account))
+		if err != nil {
+			return brazilAccountError(BrazilAccount, err)
+		}
+		if !strings.EqualFold(dv, accountDigit) {
+			return &BrazilAccountError{Field: BrazilAccountDigit, Message: fmt.Sprintf("invalid account check digit for %v", rule.Name)}
+		}
+	}
+	return nil
+}
+
+// mod11Rule returns a mod 11 check digit rule
+// the value is left padded with zeros to size digits,
+// a remainder of 10 results in ten and 11 in eleven
+func mod11Rule(size int, weights []int, ten string, eleven string) func(string) (string, error) {
+	return func(v string) (string, error) {
+		d, ok := digits(v)
+		if !ok || len(d) == 0 || len(d) > size {
+			return "", fmt.Errorf("value must be up to %v digits", size)
+		}
+		d = append(make([]int, size-len(d)), d...)
+		sum := 0
+		for i := range d {
+			sum += d[i] * weights[i]
+		}
+		switch dv := 11 - sum%11; dv {
+		case 10:
+			return ten, nil
+		case 11:
+			return eleven, nil
+		default:
+			return strconv.Itoa(dv), nil
+		}
+	}
+}
+
+// ignoreBranch adapts a single value rule to an account rule
+func ignoreBranch(rule func(string) (string, error)) func(string, string) (string, error) {
+	return func(_ string, account string) (string, error) {
+		return rule(account)
+	}
+}
+
+// itauAccount computes the Itaú account check digit
+// mod 10 over the 4 digits branch and the 5 digits account
+func itauAccount(branch string, account string) (string, error) {
+	b, ok := digits(branch)
+	if !ok || len(b) != 4 {
+		return "", &BrazilAccountError{Field: BrazilBranch, Message: "branch must be 4 digits"}
+	}
+	a, ok := digits(account)
+	if !ok || len(a) != 5 {
+		return "", fmt.Errorf("account must be 5 digits")
+	}
+	sum := 0
+	for i, v := range append(b, a...) {
+		p := v * (2 - i%2)
+		sum += p/10 + p%10
+	}
+	return strconv.Itoa((10 - sum%10) % 10), nil
+}
+
+// allEqual reports whether all digits are the same
+func allEqual(d []int) bool {
+	for _, v := range d {
+		if v != d[0] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package bankvalidate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCPF(t *testing.T) {
+	tests := map[string]error{
+		"529.982.247-25": nil,
+		"52998224725":    nil,
+		"52998224726":    fmt.Errorf("invalid CPF check digits"),
+		"11111111111":    fmt.Errorf("invalid CPF"),
+		"5299822472":     fmt.Errorf("CPF must be 11 digits"),
+	}
+	for cpf, expected := range tests {
+		assert.Equal(t, expected, CPF(cpf), cpf)
+	}
+}
+
+func TestCNPJ(t *testing.T) {
+	tests := map[string]error{
+		"11.222.333/0001-81": nil,
+		"11222333000181":     nil,
+		"11222333000182":     fmt.Errorf("invalid CNPJ check digits"),
+		"00000000000000":     fmt.Errorf("invalid CNPJ"),
+		"1122233300018":      fmt.Errorf("CNPJ must be 14 digits"),
+	}
+	for cnpj, expected := range tests {
+		assert.Equal(t, expected, CNPJ(cnpj), cnpj)
+	}
+}
+
+func TestBrazilPersonalID(t *testing.T) {
+	assert.Equal(t, nil, BrazilPersonalID("529.982.247-25"))
+	assert.Equal(t, nil, BrazilPersonalID("11.222.333/0001-81"))
+	assert.Equal(t, fmt.Errorf("CPF must be 11 digits"), BrazilPersonalID("123"))
+}
+
+func TestBrazilBankAccount(t *testing.T) {
+	tests := []struct {
+		name                                         string
+		bank, branch, branchDigit, account, accDigit string
+		expected                                     error
+	}{
+		{"banco do brasil", "001", "1234", "3", "12345", "5", nil},
+		{"banco do brasil branch", "001", "1234", "4", "12345", "5", &BrazilAccountError{Field: BrazilBranchDigit, Message: "invalid branch check digit for Banco do Brasil"}},
+		{"banco do brasil branch format", "001", "12345", "4", "12345", "5", &BrazilAccountError{Field: BrazilBranch, Message: "value must be up to 4 digits"}},
+		{"banco do brasil account", "001", "1234", "3", "12345", "X", &BrazilAccountError{Field: BrazilAccountDigit, Message: "invalid account check digit for Banco do Brasil"}},
+		{"banco do brasil account format", "001", "1234", "3", "12a45", "5", &BrazilAccountError{Field: BrazilAccount, Message: "value must be up to 8 digits"}},
+		{"itau", "341", "2545", "", "02366", "1", nil},
+		{"itau account", "341", "2545", "", "02366", "2", &BrazilAccountError{Field: BrazilAccountDigit, Message: "invalid account check digit for Itaú"}},
+		{"itau format", "341", "254", "", "02366", "1", &BrazilAccountError{Field: BrazilBranch, Message: "branch must be 4 digits"}},
+		{"itau account format", "341", "2545", "", "2366", "1", &BrazilAccountError{Field: BrazilAccount, Message: "account must be 5 digits"}},
+		{"unknown bank", "999", "1", "1", "1", "1", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err := BrazilBankAccount(test.bank, test.branch, test.branchDigit, test.account, test.accDigit)
+			assert.Equal(t, test.expected, err)
+		})
+	}
+}
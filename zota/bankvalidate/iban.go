@@ -0,0 +1,70 @@
+package bankvalidate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// IBANLengths contains the IBAN length of every country using IBAN
+// keyed by ISO 3166-1 alpha-2 country code
+var IBANLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16, "BG": 22,
+	"BH": 22, "BI": 27, "BR": 29, "BY": 28, "CH": 21, "CR": 22, "CY": 28, "CZ": 24,
+	"DE": 22, "DJ": 27, "DK": 18, "DO": 28, "EE": 20, "EG": 29, "ES": 24, "FI": 18,
+	"FK": 18, "FO": 18, "FR": 27, "GB": 22, "GE": 22, "GI": 23, "GL": 18, "GR": 27,
+	"GT": 28, "HR": 21, "HU": 28, "IE": 22, "IL": 23, "IQ": 23, "IS": 26, "IT": 27,
+	"JO": 30, "KW": 30, "KZ": 20, "LB": 28, "LC": 32, "LI": 21, "LT": 20, "LU": 20,
+	"LV": 21, "LY": 25, "MC": 27, "MD": 24, "ME": 22, "MK": 19, "MN": 20, "MR": 27,
+	"MT": 31, "MU": 30, "NI": 28, "NL": 18, "NO": 15, "OM": 23, "PK": 24, "PL": 28,
+	"PS": 29, "PT": 25, "QA": 29, "RO": 24, "RS": 22, "RU": 33, "SA": 24, "SC": 31,
+	"SD": 18, "SE": 24, "SI": 19, "SK": 24, "SM": 27, "SO": 23, "ST": 25, "SV": 28,
+	"TL": 23, "TN": 24, "TR": 26, "UA": 29, "VA": 22, "VG": 24, "XK": 20, "YE": 30,
+}
+
+// NormalizeIBAN removes the spaces of the print format
+// and returns the IBAN in upper case
+func NormalizeIBAN(iban string) string {
+	return strings.ToUpper(strings.ReplaceAll(iban, " ", ""))
+}
+
+// IBAN validates the country length and the mod-97 checksum of an IBAN
+// spaces of the print format are allowed
+func IBAN(iban string) error {
+	s := NormalizeIBAN(iban)
+	if len(s) < 5 {
+		return fmt.Errorf("invalid IBAN length")
+	}
+
+	for _, c := range s {
+		if !(c >= '0' && c <= '9') && !(c >= 'A' && c <= 'Z') {
+			return fmt.Errorf("invalid IBAN characters")
+		}
+	}
+
+	country := s[:2]
+	length, ok := IBANLengths[country]
+	if !ok {
+		return fmt.Errorf("unsupported IBAN country %v", country)
+	}
+	if len(s) != length {
+		return fmt.Errorf("invalid IBAN length for %v", country)
+	}
+	if _, ok := digits(s[2:4]); !ok {
+		return fmt.Errorf("invalid IBAN check digits")
+	}
+
+	//move the first 4 characters to the end, letters are replaced by 10..35
+	//and the remainder is computed digit by digit
+	rem := 0
+	for _, c := range s[4:] + s[:4] {
+		if c >= 'A' && c <= 'Z' {
+			rem = (rem*100 + int(c-'A') + 10) % 97
+		} else {
+			rem = (rem*10 + int(c-'0')) % 97
+		}
+	}
+	if rem != 1 {
+		return fmt.Errorf("invalid IBAN checksum")
+	}
+	return nil
+}
@@ -0,0 +1,27 @@
+package bankvalidate
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIBAN(t *testing.T) {
+	tests := map[string]error{
+		"DE89370400440532013000":        nil,
+		"DE89 3704 0044 0532 0130 00":   nil,
+		"gb82west12345698765432":        nil,
+		"NO9386011117947":               nil,
+		"BR1800360305000010009795493C1": nil,
+		"DE88370400440532013000":        fmt.Errorf("invalid IBAN checksum"),
+		"DE8937040044053201300":         fmt.Errorf("invalid IBAN length for DE"),
+		"XX89370400440532013000":        fmt.Errorf("unsupported IBAN country XX"),
+		"DE8937040044053201300!":        fmt.Errorf("invalid IBAN characters"),
+		"DEAB370400440532013000":        fmt.Errorf("invalid IBAN check digits"),
+		"DE":                            fmt.Errorf("invalid IBAN length"),
+	}
+	for iban, expected := range tests {
+		assert.Equal(t, expected, IBAN(iban), iban)
+	}
+}
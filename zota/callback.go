@@ -7,25 +7,40 @@ import (
 
 // callback struct - handle the whole callback data
 type callback struct {
-	Type                   string      `json:"type"`
-	Amount                 string      `json:"amount"`
-	Status                 string      `json:"status"`
-	OrderID                string      `json:"orderID"`
-	Currency               string      `json:"currency"`
-	ExtraData              interface{} `json:"extraData"`
-	Signature              string      `json:"signature"`
-	EndpointID             string      `json:"endpointID"`
-	CustomParam            string      `json:"customParam"`
-	ErrorMessage           string      `json:"errorMessage"`
-	CustomerEmail          string      `json:"customerEmail"`
-	MerchantOrderID        string      `json:"merchantOrderID"`
-	OriginalRequest        interface{} `json:"originalRequest"`
-	ProcessorTransactionID string      `json:"processorTransactionID"`
+	Type                   string          `json:"type"`
+	Amount                 string          `json:"amount"`
+	Status                 OrderStatusCode `json:"status"`
+	OrderID                string          `json:"orderID"`
+	Currency               string          `json:"currency"`
+	ExtraData              interface{}     `json:"extraData"`
+	Signature              string          `json:"signature"`
+	EndpointID             string          `json:"endpointID"`
+	CustomParam            string          `json:"customParam"`
+	ErrorMessage           string          `json:"errorMessage"`
+	CustomerEmail          string          `json:"customerEmail"`
+	MerchantOrderID        string          `json:"merchantOrderID"`
+	OriginalRequest        interface{}     `json:"originalRequest"`
+	ProcessorTransactionID string          `json:"processorTransactionID"`
 }
 
 // Callback parse the callback data into callback struct
 // and validate the Signature
 func (s *SDK) Callback(b []byte) (callback, error) {
+	c, err := s.parseCallback(b)
+	if err != nil {
+		return callback{}, err
+	}
+
+	if !s.acceptsEndpoint(c.EndpointID) {
+		return callback{}, fmt.Errorf("unexpected endpointID")
+	}
+
+	return c, nil
+}
+
+// parseCallback parse the callback data and validate the Signature
+// without checking the endpointID
+func (s *SDK) parseCallback(b []byte) (callback, error) {
 
 	var c callback
 	err := json.Unmarshal(b, &c)
@@ -44,6 +59,6 @@ func (s *SDK) Callback(b []byte) (callback, error) {
 // validateCallbackSign generate and validate
 // callback sign
 func (s *SDK) validateCallbackSign(c *callback) bool {
-	signature := s.sign(c.EndpointID + c.OrderID + c.MerchantOrderID + c.Status + c.Amount + c.CustomerEmail)
+	signature := s.sign(c.EndpointID + c.OrderID + c.MerchantOrderID + string(c.Status) + c.Amount + c.CustomerEmail)
 	return signature == c.Signature
 }
@@ -0,0 +1,216 @@
+package zota
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// CardBrand represents the card scheme detected from the card number IIN/BIN
+type CardBrand string
+
+const (
+	CardBrandUnknown    CardBrand = ""
+	CardBrandVisa       CardBrand = "VISA"
+	CardBrandMastercard CardBrand = "MASTERCARD"
+	CardBrandAmex       CardBrand = "AMEX"
+	CardBrandDiscover   CardBrand = "DISCOVER"
+	CardBrandJCB        CardBrand = "JCB"
+	CardBrandUnionPay   CardBrand = "UNIONPAY"
+	CardBrandDiners     CardBrand = "DINERS"
+	CardBrandMaestro    CardBrand = "MAESTRO"
+	CardBrandMir        CardBrand = "MIR"
+)
+
+// cardRule describes IIN ranges, allowed PAN lengths and CVV length of a brand
+type cardRule struct {
+	brand   CardBrand
+	ranges  [][2]int // inclusive IIN prefix ranges, both ends with the same number of digits
+	lengths []int
+	cvv     int
+}
+
+// cardRules are checked in order, the first matching range wins
+var cardRules = []cardRule{
+	{CardBrandAmex, [][2]int{{34, 34}, {37, 37}}, []int{15}, 4},
+	{CardBrandDiners, [][2]int{{300, 305}, {36, 36}, {38, 39}}, []int{14, 15, 16, 17, 18, 19}, 3},
+	{CardBrandJCB, [][2]int{{3528, 3589}}, []int{16, 17, 18, 19}, 3},
+	{CardBrandVisa, [][2]int{{4, 4}}, []int{13, 16, 19}, 3},
+	{CardBrandMir, [][2]int{{2200, 2204}}, []int{16, 17, 18, 19}, 3},
+	{CardBrandMastercard, [][2]int{{51, 55}, {2221, 2720}}, []int{16}, 3},
+	{CardBrandMaestro, [][2]int{{5018, 5018}, {5020, 5020}, {5038, 5038}, {5893, 5893}, {6304, 6304}, {6759, 6759}, {6761, 6763}}, []int{12, 13, 14, 15, 16, 17, 18, 19}, 3},
+	{CardBrandDiscover, [][2]int{{6011, 6011}, {644, 649}, {65, 65}}, []int{16, 17, 18, 19}, 3},
+	{CardBrandUnionPay, [][2]int{{62, 62}}, []int{16, 17, 18, 19}, 3},
+}
+
+// timeNow returns the current time, replaced in tests
+var timeNow = time.Now
+
+// LuhnValid checks the card number against the Luhn (mod 10) algorithm
+// returns false if the number contains non digit characters
+func LuhnValid(number string) bool {
+	return luhnValid([]byte(number))
+}
+
+// luhnValid is the []byte version of LuhnValid
+func luhnValid(number []byte) bool {
+	if len(number) == 0 {
+		return false
+	}
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c < '0' || c > '9' {
+			return false
+		}
+		d := int(c - '0')
+		if double {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// DetectCardBrand returns the card brand based on the IIN/BIN ranges
+// of the card number, CardBrandUnknown if there is no match
+func DetectCardBrand(number string) CardBrand {
+	if r, ok := findCardRule([]byte(number)); ok {
+		return r.brand
+	}
+	return CardBrandUnknown
+}
+
+// findCardRule returns the cardRule matching the card number prefix
+func findCardRule(number []byte) (cardRule, bool) {
+	for _, r := range cardRules {
+		for _, rng := range r.ranges {
+			digits := len(strconv.Itoa(rng[0]))
+			if len(number) < digits || !onlyDigits(number[:digits]) {
+				continue
+			}
+			prefix := 0
+			for _, c := range number[:digits] {
+				prefix = prefix*10 + int(c-'0')
+			}
+			if prefix >= rng[0] && prefix <= rng[1] {
+				return r, true
+			}
+		}
+	}
+	return cardRule{}, false
+}
+
+// NormalizeCardExpiry parses the expiration month and year of a card
+// year can be 2 digits ("21") or 4 digits ("2021")
+// returns the month and the 4 digits year
+func NormalizeCardExpiry(month string, year string) (int, int, error) {
+	m, err := strconv.Atoi(month)
+	if err != nil || len(month) > 2 || m < 1 || m > 12 {
+		return 0, 0, fmt.Errorf("invalid expiration month")
+	}
+	y, err := strconv.Atoi(year)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid expiration year")
+	}
+	switch len(year) {
+	case 2:
+		y += 2000
+	case 4:
+	default:
+		return 0, 0, fmt.Errorf("invalid expiration year")
+	}
+	return m, y, nil
+}
+
+// validateCard checks the card data of an order
+// and returns the list of failed fields
+// number and cvv are []byte so SensitiveCard data is never copied to a string
+// the card values are never part of the messages
+func validateCard(number []byte, holder, month, year string, cvv []byte) (errs FieldErrors) {
+
+	//card number
+	rule, known := findCardRule(number)
+	switch {
+	case len(number) == 0:
+		errs.add("CardNumber", "is required")
+	case !onlyDigits(number):
+		errs.add("CardNumber", "must contain digits only")
+	case !luhnValid(number):
+		errs.add("CardNumber", "failed Luhn check")
+	case known && !containsInt(rule.lengths, len(number)):
+		errs.add("CardNumber", fmt.Sprintf("has invalid length for %v", rule.brand))
+	case !known && (len(number) < 12 || len(number) > 19):
+		errs.add("CardNumber", "has invalid length")
+	}
+
+	//card holder name
+	name := strings.TrimSpace(holder)
+	switch {
+	case name == "":
+		errs.add("CardHolderName", "is required")
+	case len([]rune(name)) < 2 || len([]rune(name)) > 64:
+		errs.add("CardHolderName", "must be between 2 and 64 characters")
+	case strings.IndexFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsSpace(r) && !strings.ContainsRune("'-.,", r)
+	}) != -1:
+		errs.add("CardHolderName", "contains invalid characters")
+	}
+
+	//expiration
+	m, y, err := NormalizeCardExpiry(month, year)
+	if err != nil {
+		if strings.Contains(err.Error(), "month") {
+			errs.add("CardExpirationMonth", "is invalid")
+		} else {
+			errs.add("CardExpirationYear", "is invalid")
+		}
+	} else {
+		now := timeNow()
+		if y < now.Year() || (y == now.Year() && m < int(now.Month())) {
+			errs.add("CardExpirationYear", "card is expired")
+		}
+	}
+
+	//cvv
+	cvvLen := len(cvv)
+	switch {
+	case cvvLen == 0:
+		errs.add("CardCvv", "is required")
+	case !onlyDigits(cvv):
+		errs.add("CardCvv", "must contain digits only")
+	case known && cvvLen != rule.cvv:
+		errs.add("CardCvv", fmt.Sprintf("must be %v digits for %v", rule.cvv, rule.brand))
+	case !known && (cvvLen < 3 || cvvLen > 4):
+		errs.add("CardCvv", "must be 3 or 4 digits")
+	}
+
+	return errs
+}
+
+// onlyDigits reports whether b contains only ASCII digits
+func onlyDigits(b []byte) bool {
+	for _, c := range b {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// containsInt reports whether v is present in list
+func containsInt(list []int, v int) bool {
+	for _, i := range list {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
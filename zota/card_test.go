@@ -0,0 +1,151 @@
+package zota
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLuhnValid(t *testing.T) {
+	assert.True(t, LuhnValid("4111111111111111"))
+	assert.True(t, LuhnValid("378282246310005"))
+	assert.False(t, LuhnValid("4111111111111112"))
+	assert.False(t, LuhnValid("4111 1111 1111 1111"))
+	assert.False(t, LuhnValid(""))
+}
+
+func TestDetectCardBrand(t *testing.T) {
+	tests := map[string]CardBrand{
+		"4111111111111111": CardBrandVisa,
+		"5555555555554444": CardBrandMastercard,
+		"2223003122003222": CardBrandMastercard,
+		"378282246310005":  CardBrandAmex,
+		"6011111111111117": CardBrandDiscover,
+		"6500000000000002": CardBrandDiscover,
+		"3530111333300000": CardBrandJCB,
+		"6200000000000005": CardBrandUnionPay,
+		"30569309025904":   CardBrandDiners,
+		"2200000000000004": CardBrandMir,
+		"6759649826438453": CardBrandMaestro,
+		"9999999999999995": CardBrandUnknown,
+		"":                 CardBrandUnknown,
+	}
+	for number, brand := range tests {
+		assert.Equal(t, brand, DetectCardBrand(number), number)
+	}
+}
+
+func TestNormalizeCardExpiry(t *testing.T) {
+	m, y, err := NormalizeCardExpiry("1", "21")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, m)
+	assert.Equal(t, 2021, y)
+
+	m, y, err = NormalizeCardExpiry("12", "2030")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 12, m)
+	assert.Equal(t, 2030, y)
+
+	_, _, err = NormalizeCardExpiry("13", "2030")
+	assert.Equal(t, fmt.Errorf("invalid expiration month"), err)
+
+	_, _, err = NormalizeCardExpiry("01", "203")
+	assert.Equal(t, fmt.Errorf("invalid expiration year"), err)
+}
+
+func Test_ValidateCard(t *testing.T) {
+	timeNow = func() time.Time { return time.Date(2021, 6, 15, 0, 0, 0, 0, time.UTC) }
+	defer func() { timeNow = time.Now }()
+
+	tests := []test{
+		{
+			name:     "valid visa",
+			mock:     []string{"4111111111111111", "John Doe", "06", "21", "123"},
+			expected: FieldErrors(nil),
+		}, {
+			name:     "valid amex",
+			mock:     []string{"378282246310005", "Jean-Luc O'Neil", "12", "2025", "1234"},
+			expected: FieldErrors(nil),
+		}, {
+			name: "missing data",
+			mock: []string{"", "", "", "", ""},
+			expected: FieldErrors{
+				{Field: "CardNumber", Message: "is required"},
+				{Field: "CardHolderName", Message: "is required"},
+				{Field: "CardExpirationMonth", Message: "is invalid"},
+				{Field: "CardCvv", Message: "is required"},
+			},
+		}, {
+			name: "invalid data",
+			mock: []string{"4111111111111112", "J0hn", "05", "21", "12a"},
+			expected: FieldErrors{
+				{Field: "CardNumber", Message: "failed Luhn check"},
+				{Field: "CardHolderName", Message: "contains invalid characters"},
+				{Field: "CardExpirationYear", Message: "card is expired"},
+				{Field: "CardCvv", Message: "must contain digits only"},
+			},
+		}, {
+			name: "brand rules",
+			mock: []string{"4111111111111111110", "John Doe", "01", "2022", "1234"},
+			expected: FieldErrors{
+				{Field: "CardCvv", Message: "must be 3 digits for VISA"},
+			},
+		}, {
+			name: "brand length",
+			mock: []string{"37828224631003", "John Doe", "01", "2022", "1234"},
+			expected: FieldErrors{
+				{Field: "CardNumber", Message: "has invalid length for AMEX"},
+			},
+		}, {
+			name:     "mastercard length",
+			mock:     []string{"5105105105105100", "John Doe", "1", "2022", "123"},
+			expected: FieldErrors(nil),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			a := test.mock.([]string)
+			errs := validateCard([]byte(a[0]), a[1], a[2], a[3], []byte(a[4]))
+			assert.Equal(t, test.expected, errs)
+		})
+	}
+}
+
+func Test_ValidateCardNeverLeaksData(t *testing.T) {
+	errs := validateCard([]byte("4111111111111112"), "J0hn", "05", "2000", []byte("9876"))
+	assert.NotEqual(t, nil, errs.err())
+	assert.False(t, strings.Contains(errs.Error(), "4111111111111112"))
+	assert.False(t, strings.Contains(errs.Error(), "9876"))
+}
+
+func TestDeposit_ValidateCCCardData(t *testing.T) {
+	order := DepositCCOrder{
+		MerchantOrderID:     "134e4f44t651",
+		MerchantOrderDesc:   "Test order description",
+		OrderAmount:         "500",
+		OrderCurrency:       "MYR",
+		CustomerEmail:       "customer@email-address.com",
+		CustomerLastName:    "Doe",
+		CustomerAddress:     "The Swan, Jungle St. 108",
+		CustomerCountryCode: "US",
+		CustomerCity:        "Los Angeles",
+		CustomerZipCode:     "84280",
+		CustomerPhone:       "+1 420-100-1000",
+		CustomerIP:          "127.0.0.1",
+		RedirectURL:         "https://some.endpoint/redirect",
+		CheckoutURL:         "https://some.endpoint/checkout",
+		CardHolderName:      "TEST TEST",
+		CardNumber:          "4222222222347466",
+		CardExpirationMonth: "01",
+		CardExpirationYear:  "2099",
+		CardCvv:             "111",
+	}
+
+	err := order.validate()
+	assert.Equal(t, FieldErrors{{Field: "CardNumber", Message: "failed Luhn check"}}, err)
+	assert.Equal(t, "CardNumber failed Luhn check", err.Error())
+}
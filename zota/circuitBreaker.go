@@ -0,0 +1,215 @@
+package zota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned without sending the request
+// while the circuit of the operation is open
+var ErrCircuitOpen = errors.New("circuit open")
+
+// CircuitState represents the state of the circuit of an operation
+type CircuitState int
+
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+// String returns the name of the state
+func (c CircuitState) String() string {
+	switch c {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("CircuitState(%d)", int(c))
+}
+
+// CircuitBreakerConfig configures a CircuitBreaker
+// zero values are replaced by the defaults
+type CircuitBreakerConfig struct {
+	// FailureThreshold is the number of consecutive transport errors or 5xx responses
+	// opening the circuit, 5 by default
+	FailureThreshold int
+	// OpenTimeout is the time the circuit stays open before probing, 30 seconds by default
+	OpenTimeout time.Duration
+	// HalfOpenProbes is the number of probe requests allowed at once while half-open, 1 by default
+	HalfOpenProbes int
+	// OnStateChange is called on every state change of a circuit
+	OnStateChange func(op Operation, from CircuitState, to CircuitState)
+}
+
+// CircuitBreaker fails the requests fast while the Zota API is down
+// every operation has its own circuit:
+// closed lets the requests through and counts the consecutive failures,
+// open fails the requests with ErrCircuitOpen until OpenTimeout is elapsed,
+// half-open lets probe requests through, a success closes the circuit and a failure opens it again
+// requests cancelled by their context are neither a success nor a failure
+// it is safe for concurrent use
+type CircuitBreaker struct {
+	cfg CircuitBreakerConfig
+
+	mu       sync.Mutex
+	circuits map[Operation]*circuit
+}
+
+// circuit is the state of the circuit of an operation
+type circuit struct {
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probes   int
+}
+
+// NewCircuitBreaker creates a CircuitBreaker with all the circuits closed
+func NewCircuitBreaker(cfg CircuitBreakerConfig) *CircuitBreaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = 30 * time.Second
+	}
+	if cfg.HalfOpenProbes <= 0 {
+		cfg.HalfOpenProbes = 1
+	}
+	return &CircuitBreaker{
+		cfg:      cfg,
+		circuits: map[Operation]*circuit{},
+	}
+}
+
+// State returns the state of the circuit of the operation
+// an open circuit past its OpenTimeout is reported half-open
+func (b *CircuitBreaker) State(op Operation) CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c, ok := b.circuits[op]
+	if !ok {
+		return CircuitClosed
+	}
+	if c.state == CircuitOpen && timeNow().Sub(c.openedAt) >= b.cfg.OpenTimeout {
+		return CircuitHalfOpen
+	}
+	return c.state
+}
+
+// Reset closes the circuit of the operation
+func (b *CircuitBreaker) Reset(op Operation) {
+	b.mu.Lock()
+	from := b.circuit(op).state
+	b.circuits[op] = &circuit{}
+	b.mu.Unlock()
+
+	b.changed(op, from, CircuitClosed)
+}
+
+// check returns ErrCircuitOpen while the circuit of the operation is open
+// without taking a probe, the requests are checked before waiting for the RateLimiter
+// a nil CircuitBreaker allows every request
+func (b *CircuitBreaker) check(op Operation) error {
+	if b == nil {
+		return nil
+	}
+	if b.State(op) == CircuitOpen {
+		return fmt.Errorf("%v %w", op, ErrCircuitOpen)
+	}
+	return nil
+}
+
+// allow reports whether a request of the operation can be sent
+// returns ErrCircuitOpen if not, otherwise a func to call with the outcome of the request
+// a nil CircuitBreaker allows every request
+func (b *CircuitBreaker) allow(op Operation) (func(ctx context.Context, code int, err error), error) {
+	if b == nil {
+		return func(context.Context, int, error) {}, nil
+	}
+
+	b.mu.Lock()
+	c := b.circuit(op)
+	from := c.state
+	if c.state == CircuitOpen && timeNow().Sub(c.openedAt) >= b.cfg.OpenTimeout {
+		c.state = CircuitHalfOpen
+		c.probes = 0
+	}
+	probe := c.state == CircuitHalfOpen
+	allowed := c.state == CircuitClosed || (probe && c.probes < b.cfg.HalfOpenProbes)
+	if allowed && probe {
+		c.probes++
+	}
+	to := c.state
+	b.mu.Unlock()
+
+	b.changed(op, from, to)
+	if !allowed {
+		return nil, fmt.Errorf("%v %w", op, ErrCircuitOpen)
+	}
+	return func(ctx context.Context, code int, err error) {
+		b.done(op, probe, ctx.Err() != nil, err != nil || code >= 500)
+	}, nil
+}
+
+// done records the outcome of a request
+func (b *CircuitBreaker) done(op Operation, probe bool, cancelled bool, failed bool) {
+	b.mu.Lock()
+	c := b.circuit(op)
+	from := c.state
+	if probe && c.state == CircuitHalfOpen {
+		c.probes--
+	}
+
+	switch {
+	case cancelled:
+	case !failed:
+		c.failures = 0
+		if c.state == CircuitHalfOpen {
+			c.state = CircuitClosed
+		}
+	case c.state == CircuitHalfOpen:
+		c.open()
+	case c.state == CircuitClosed:
+		c.failures++
+		if c.failures >= b.cfg.FailureThreshold {
+			c.open()
+		}
+	}
+	to := c.state
+	b.mu.Unlock()
+
+	b.changed(op, from, to)
+}
+
+// circuit returns the circuit of the operation
+// b.mu must be held
+func (b *CircuitBreaker) circuit(op Operation) *circuit {
+	c, ok := b.circuits[op]
+	if !ok {
+		c = &circuit{}
+		b.circuits[op] = c
+	}
+	return c
+}
+
+// open opens the circuit
+func (c *circuit) open() {
+	c.state = CircuitOpen
+	c.openedAt = timeNow()
+	c.failures = 0
+	c.probes = 0
+}
+
+// changed calls the OnStateChange hook if the state changed
+func (b *CircuitBreaker) changed(op Operation, from CircuitState, to CircuitState) {
+	if from != to && b.cfg.OnStateChange != nil {
+		b.cfg.OnStateChange(op, from, to)
+	}
+}
@@ -0,0 +1,167 @@
+package zota
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ClientMockStatus returns the response with the StatusCode
+// implement httpClient interface
+type ClientMockStatus struct {
+	StatusCode int
+	calls      int
+}
+
+func (c *ClientMockStatus) Do(req *http.Request) (*http.Response, error) {
+	c.calls++
+	return &http.Response{
+		StatusCode: c.StatusCode,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"code":"500","message":"internal error"}`))),
+	}, nil
+}
+
+func TestCircuitState_String(t *testing.T) {
+	assert.Equal(t, "closed", CircuitClosed.String())
+	assert.Equal(t, "open", CircuitOpen.String())
+	assert.Equal(t, "half-open", CircuitHalfOpen.String())
+	assert.Equal(t, "CircuitState(7)", CircuitState(7).String())
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	var changes []string
+	b := NewCircuitBreaker(CircuitBreakerConfig{
+		FailureThreshold: 3,
+		OpenTimeout:      time.Minute,
+		OnStateChange: func(op Operation, from CircuitState, to CircuitState) {
+			changes = append(changes, fmt.Sprintf("%v %v>%v", op, from, to))
+		},
+	})
+	ctx := context.Background()
+	fail := func() {
+		done, err := b.allow(OperationDeposit)
+		assert.Equal(t, nil, err)
+		done(ctx, 0, fmt.Errorf("do error"))
+	}
+
+	//a success resets the consecutive failures
+	fail()
+	fail()
+	done, _ := b.allow(OperationDeposit)
+	done(ctx, 200, nil)
+	fail()
+	fail()
+	assert.Equal(t, CircuitClosed, b.State(OperationDeposit))
+
+	//a 5xx is a failure
+	done, _ = b.allow(OperationDeposit)
+	done(ctx, 503, nil)
+	assert.Equal(t, CircuitOpen, b.State(OperationDeposit))
+
+	_, err := b.allow(OperationDeposit)
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+	assert.Equal(t, "deposit circuit open", err.Error())
+
+	//other operations have their own circuit
+	_, err = b.allow(OperationPayout)
+	assert.Equal(t, nil, err)
+
+	//one probe after the OpenTimeout, a failure opens the circuit again
+	now = now.Add(time.Minute)
+	assert.Equal(t, CircuitHalfOpen, b.State(OperationDeposit))
+	probe, err := b.allow(OperationDeposit)
+	assert.Equal(t, nil, err)
+	_, err = b.allow(OperationDeposit)
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+	probe(ctx, 500, nil)
+	assert.Equal(t, CircuitOpen, b.State(OperationDeposit))
+
+	//a cancelled probe frees its slot, a successful probe closes the circuit
+	now = now.Add(time.Minute)
+	probe, _ = b.allow(OperationDeposit)
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	probe(cancelled, 0, context.Canceled)
+	assert.Equal(t, CircuitHalfOpen, b.State(OperationDeposit))
+	probe, err = b.allow(OperationDeposit)
+	assert.Equal(t, nil, err)
+	probe(ctx, 200, nil)
+	assert.Equal(t, CircuitClosed, b.State(OperationDeposit))
+
+	fail()
+	fail()
+	fail()
+	b.Reset(OperationDeposit)
+	assert.Equal(t, CircuitClosed, b.State(OperationDeposit))
+
+	assert.Equal(t, []string{
+		"deposit closed>open",
+		"deposit open>half-open",
+		"deposit half-open>open",
+		"deposit open>half-open",
+		"deposit half-open>closed",
+		"deposit closed>open",
+		"deposit open>closed",
+	}, changes)
+}
+
+func TestSDK_CircuitBreaker(t *testing.T) {
+	client := &ClientMockSequence{responses: []string{""}}
+	sdk := testPollSDK(client)
+	sdk.CircuitBreaker = NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 2})
+
+	order := OrderStatus{MerchantOrderID: "m-1", OrderID: "1"}
+	for i := 0; i < 2; i++ {
+		_, err := sdk.OrderStatus(order)
+		assert.Equal(t, fmt.Errorf("do error"), err)
+	}
+	_, err := sdk.OrderStatus(order)
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+	assert.Equal(t, 2, client.Calls())
+
+	//deposits are not affected by the order status circuit
+	status := &ClientMockStatus{StatusCode: 502}
+	sdk.HttpClient = status
+	deposit := testDepositOrder()
+	for i := 0; i < 2; i++ {
+		res, err := sdk.Deposit(deposit)
+		assert.Equal(t, nil, err)
+		assert.Equal(t, "500", res.Code)
+	}
+	_, err = sdk.Deposit(deposit)
+	assert.Equal(t, "deposit circuit open", err.Error())
+	assert.Equal(t, 2, status.calls)
+}
+
+func TestSDK_CircuitBreakerBeforeRateLimiter(t *testing.T) {
+	client := &ClientMockSequence{responses: []string{""}}
+	sdk := testPollSDK(client)
+	sdk.CircuitBreaker = NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1})
+	sdk.RateLimiter = NewRateLimiter(RateLimiterConfig{Operations: map[Operation]RateLimit{OperationOrderStatus: {Rate: 0.001}}})
+
+	//the first request takes the only token and opens the circuit
+	order := OrderStatus{MerchantOrderID: "m-1", OrderID: "1"}
+	_, err := sdk.OrderStatus(order)
+	assert.Equal(t, fmt.Errorf("do error"), err)
+
+	//the open circuit fails without waiting for the next token
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	start := time.Now()
+	_, err = sdk.OrderStatusContext(ctx, order)
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+	assert.True(t, time.Since(start) < 100*time.Millisecond)
+	assert.Equal(t, int64(1), sdk.RateLimiter.Stats()[OperationOrderStatus][PriorityInteractive].Requests)
+	assert.Equal(t, 1, client.Calls())
+}
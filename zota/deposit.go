@@ -1,6 +1,7 @@
 package zota
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -38,6 +39,11 @@ type DepositResult struct {
 	Code    string            `json:"code"`
 	Data    DepositResultData `json:"data"`
 	Message string            `json:"message"`
+
+	//EndpointID is the endpoint the order was sent to
+	EndpointID string `json:"-"`
+	//DryRun is set if the order was not sent, see DryRunMode
+	DryRun bool `json:"-"`
 }
 
 type DepositResultData struct {
@@ -52,15 +58,15 @@ var mockedDepositResult *DepositResult
 // generate sign and
 // init a deposit request to Zota API
 func (s *SDK) Deposit(d DepositOrder) (res DepositResult, err error) {
+	return s.DepositContext(context.Background(), d)
+}
 
-	//validate that SDK is properly initialized
-	err = s.validate()
-	if err != nil {
-		return
-	}
+// DepositContext is Deposit with a context
+// the request is cancelled when ctx is done
+func (s *SDK) DepositContext(ctx context.Context, d DepositOrder) (res DepositResult, err error) {
 
-	//validate that DepositOrder is properly initialized
-	err = d.validate()
+	//validate, route and sign the DepositOrder
+	r, err := s.PrepareDeposit(d)
 	if err != nil {
 		return
 	}
@@ -73,25 +79,51 @@ func (s *SDK) Deposit(d DepositOrder) (res DepositResult, err error) {
 		return
 	}
 
-	//generate signature
-	d.Signature = s.sign(s.EndpointID, d.MerchantOrderID, d.OrderAmount, d.CustomerEmail)
+	resp, err := s.Send(ctx, r)
+	if err != nil {
+		return
+	}
+	return resp.DepositResult()
+}
 
-	deposit, err := json.Marshal(d)
+// PrepareDeposit init validation of the SDK struct and the DepositOrder
+// generate sign and
+// returns the deposit request to be sent later with Send
+func (s *SDK) PrepareDeposit(d DepositOrder) (r PreparedRequest, err error) {
+
+	//validate that SDK is properly initialized
+	err = s.validate()
+	if err != nil {
+		return
+	}
+
+	//validate that DepositOrder is properly initialized
+	err = d.validate()
+	if err != nil {
+		return
+	}
+
+	//validate the CustomerBankCode against the bank catalog
+	err = s.bankCatalog().validateBankCode(d.OrderCurrency, d.CustomerBankCode, OperationDeposit)
 	if err != nil {
 		return
 	}
 
-	_, body, err := s.httpDo(http.MethodPost, fmt.Sprintf("%v/api/v1/deposit/request/%v/", s.ApiBaseURL, s.EndpointID), deposit)
+	//select the endpoint of the order
+	endpointID, err := s.endpoint(EndpointRoute{Operation: OperationDeposit, PaymentMethod: PaymentMethodBank, Currency: d.OrderCurrency, Country: d.CustomerCountryCode})
 	if err != nil {
 		return
 	}
 
-	err = json.Unmarshal(body, &res)
+	//generate signature
+	d.Signature = s.sign(endpointID, d.MerchantOrderID, d.OrderAmount, d.CustomerEmail)
+
+	deposit, err := json.Marshal(d)
 	if err != nil {
-		err = fmt.Errorf("json Unmarshal err:%v", err)
 		return
 	}
 
+	r = newPreparedRequest(OperationDeposit, endpointID, d.MerchantOrderID, http.MethodPost, fmt.Sprintf("%v/api/v1/deposit/request/%v/", s.ApiBaseURL, endpointID), deposit, d.Signature)
 	return
 }
 
@@ -1,6 +1,7 @@
 package zota
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -32,11 +33,16 @@ type DepositCCOrder struct {
 	Signature           string `json:"signature"`
 
 	//credit card data
-	CardNumber          string `json:"cardNumber"`
+	//CardNumber and CardCvv can be replaced by Card
+	CardNumber          string `json:"cardNumber,omitempty"`
 	CardHolderName      string `json:"cardHolderName"`
 	CardExpirationMonth string `json:"cardExpirationMonth"`
 	CardExpirationYear  string `json:"cardExpirationYear"`
-	CardCvv             string `json:"cardCvv"`
+	CardCvv             string `json:"cardCvv,omitempty"`
+
+	//Card holds the card number and CVV outside of the json encoded order
+	//it is written to the request body only and wiped afterwards
+	Card *SensitiveCard `json:"-"`
 }
 
 // DepositCCResult represents credit card deposit response from Zota API
@@ -44,12 +50,17 @@ type DepositCCResult struct {
 	Code    string              `json:"code"`
 	Data    DepositCCResultData `json:"data"`
 	Message string              `json:"message"`
+
+	//EndpointID is the endpoint the order was sent to
+	EndpointID string `json:"-"`
+	//DryRun is set if the order was not sent, see DryRunMode
+	DryRun bool `json:"-"`
 }
 
 type DepositCCResultData struct {
-	Status          string `json:"status"`
-	MerchantOrderID string `json:"merchantOrderID"`
-	OrderID         string `json:"orderID"`
+	Status          OrderStatusCode `json:"status"`
+	MerchantOrderID string          `json:"merchantOrderID"`
+	OrderID         string          `json:"orderID"`
 }
 
 var mockedDepositCCResult *DepositCCResult
@@ -58,6 +69,15 @@ var mockedDepositCCResult *DepositCCResult
 // generate sign and
 // init a credit card deposit request to Zota API
 func (s *SDK) DepositCC(d DepositCCOrder) (res DepositCCResult, err error) {
+	return s.DepositCCContext(context.Background(), d)
+}
+
+// DepositCCContext is DepositCC with a context
+// the request is cancelled when ctx is done
+func (s *SDK) DepositCCContext(ctx context.Context, d DepositCCOrder) (res DepositCCResult, err error) {
+
+	//the SensitiveCard is used for a single request, it is wiped on every return
+	defer d.Card.Wipe()
 
 	//validate that SDK is properly initialized
 	err = s.validate()
@@ -71,6 +91,12 @@ func (s *SDK) DepositCC(d DepositCCOrder) (res DepositCCResult, err error) {
 		return
 	}
 
+	//select the endpoint of the order
+	endpointID, err := s.endpoint(EndpointRoute{Operation: OperationDeposit, PaymentMethod: PaymentMethodCard, Currency: d.OrderCurrency, Country: d.CustomerCountryCode})
+	if err != nil {
+		return
+	}
+
 	//if mockedDepositCCResult is set return it as response
 	//only for testing
 	if mockedDepositCCResult != nil {
@@ -80,23 +106,36 @@ func (s *SDK) DepositCC(d DepositCCOrder) (res DepositCCResult, err error) {
 	}
 
 	//generate signature
-	d.Signature = s.sign(s.EndpointID, d.MerchantOrderID, d.OrderAmount, d.CustomerEmail)
+	d.Signature = s.sign(endpointID, d.MerchantOrderID, d.OrderAmount, d.CustomerEmail)
 
 	deposit, err := json.Marshal(d)
 	if err != nil {
 		return
 	}
 
-	_, body, err := s.httpDo(http.MethodPost, fmt.Sprintf("%v/api/v1/deposit/request/%v/", s.ApiBaseURL, s.EndpointID), deposit)
+	//write the SensitiveCard data to the request body only
+	//and zero the body once the request is done
+	if d.Card != nil {
+		deposit, err = d.Card.appendDepositCCBody(deposit)
+		if err != nil {
+			return
+		}
+		defer zero(deposit)
+	}
+
+	//card deposits are not preparable, the card data must not be persisted
+	resp, err := s.Send(ctx, newPreparedRequest(OperationDeposit, endpointID, d.MerchantOrderID, http.MethodPost, fmt.Sprintf("%v/api/v1/deposit/request/%v/", s.ApiBaseURL, endpointID), deposit, d.Signature))
 	if err != nil {
 		return
 	}
 
-	err = json.Unmarshal(body, &res)
+	err = json.Unmarshal(resp.Body, &res)
 	if err != nil {
 		err = fmt.Errorf("json Unmarshal err:%v", err)
 		return
 	}
+	res.EndpointID = endpointID
+	res.DryRun = resp.DryRun
 
 	return
 }
@@ -110,6 +149,7 @@ func (mock *DepositCCResult) SetMockResponse() {
 
 // validate the instance of DepositCCOrder
 // if not valid returns an error
+// credit card failures are returned as FieldErrors
 func (d *DepositCCOrder) validate() error {
 	required := []string{"MerchantOrderID", "MerchantOrderDesc", "OrderAmount", "OrderCurrency", "MerchantOrderDesc", "CustomerEmail", "CustomerLastName", "CustomerAddress", "CustomerCountryCode", "CustomerCity", "CustomerZipCode", "CustomerPhone", "CustomerIP", "RedirectURL", "CheckoutURL"}
 	for _, fieldName := range required {
@@ -120,5 +160,16 @@ func (d *DepositCCOrder) validate() error {
 			return fmt.Errorf("%v is required", fieldName)
 		}
 	}
-	return nil
+
+	//validate the credit card data
+	if d.Card != nil {
+		if d.CardNumber != "" || d.CardCvv != "" {
+			return FieldErrors{{Field: "Card", Message: "cannot be combined with CardNumber or CardCvv"}}
+		}
+		if d.Card.IsWiped() {
+			return FieldErrors{{Field: "Card", Message: "has been wiped"}}
+		}
+		return validateCard(d.Card.number, d.CardHolderName, d.CardExpirationMonth, d.CardExpirationYear, d.Card.cvv).err()
+	}
+	return validateCard([]byte(d.CardNumber), d.CardHolderName, d.CardExpirationMonth, d.CardExpirationYear, []byte(d.CardCvv)).err()
 }
@@ -99,6 +99,7 @@ func Test_DepositCC(t *testing.T) {
 					MerchantOrderID: "QvE8dZshpKhaOmHY",
 					OrderID:         "8b3a6b89697e8ac8f45d964bcc90c7ba41764acd",
 				},
+				EndpointID: "503368",
 			},
 			expectedError: nil,
 			mockSDK: SDK{
@@ -131,9 +132,9 @@ func Test_DepositCC(t *testing.T) {
 				Language:            "EN",
 				// credit card data
 				CardHolderName:      "TEST TEST",
-				CardNumber:          "4222222222347466",
+				CardNumber:          "4222222222222220",
 				CardExpirationMonth: "01",
-				CardExpirationYear:  "21",
+				CardExpirationYear:  "2099",
 				CardCvv:             "111",
 			},
 		},
@@ -141,8 +142,9 @@ func Test_DepositCC(t *testing.T) {
 			// --------------------Test Http Error Api response----------------------
 			name: "Deposit Http Request API Error",
 			expectedDepositCCResult: DepositCCResult{
-				Code:    "400",
-				Message: "bad request",
+				Code:       "400",
+				Message:    "bad request",
+				EndpointID: "503368",
 			},
 			expectedError: nil,
 			mockSDK: SDK{
@@ -175,9 +177,9 @@ func Test_DepositCC(t *testing.T) {
 				Language:            "EN",
 				// credit card data
 				CardHolderName:      "TEST TEST",
-				CardNumber:          "4222222222347466",
+				CardNumber:          "4222222222222220",
 				CardExpirationMonth: "01",
-				CardExpirationYear:  "21",
+				CardExpirationYear:  "2099",
 				CardCvv:             "111",
 			},
 		},
@@ -219,9 +221,9 @@ func Test_DepositCC(t *testing.T) {
 				Language:            "EN",
 				// credit card data
 				CardHolderName:      "TEST TEST",
-				CardNumber:          "4222222222347466",
+				CardNumber:          "4222222222222220",
 				CardExpirationMonth: "01",
-				CardExpirationYear:  "21",
+				CardExpirationYear:  "2099",
 				CardCvv:             "111",
 			},
 		},
@@ -260,9 +262,9 @@ func Test_DepositCC(t *testing.T) {
 				Language:            "EN",
 				// credit card data
 				CardHolderName:      "TEST TEST",
-				CardNumber:          "4222222222347466",
+				CardNumber:          "4222222222222220",
 				CardExpirationMonth: "01",
-				CardExpirationYear:  "21",
+				CardExpirationYear:  "2099",
 				CardCvv:             "111",
 			},
 		}, {
@@ -349,9 +351,9 @@ func TestDepositCCResult_SetMockResponseCC(t *testing.T) {
 		Language:            "EN",
 		// credit card data
 		CardHolderName:      "TEST TEST",
-		CardNumber:          "4222222222347466",
+		CardNumber:          "4222222222222220",
 		CardExpirationMonth: "01",
-		CardExpirationYear:  "21",
+		CardExpirationYear:  "2099",
 		CardCvv:             "111",
 	})
 
@@ -382,9 +384,9 @@ func TestDepositCCResult_SetMockResponseCC(t *testing.T) {
 		Language:            "EN",
 		// credit card data
 		CardHolderName:      "TEST TEST",
-		CardNumber:          "4222222222347466",
+		CardNumber:          "4222222222222220",
 		CardExpirationMonth: "01",
-		CardExpirationYear:  "21",
+		CardExpirationYear:  "2099",
 		CardCvv:             "111",
 	})
 
@@ -420,9 +422,9 @@ func TestDeposit_ValidateCC(t *testing.T) {
 				Language:            "EN",
 				// credit card data
 				CardHolderName:      "TEST TEST",
-				CardNumber:          "4222222222347466",
+				CardNumber:          "4222222222222220",
 				CardExpirationMonth: "01",
-				CardExpirationYear:  "21",
+				CardExpirationYear:  "2099",
 				CardCvv:             "111",
 			},
 			expectedError: nil,
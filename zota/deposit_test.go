@@ -99,6 +99,7 @@ func Test_Deposit(t *testing.T) {
 					MerchantOrderID: "QvE8dZshpKhaOmHY",
 					OrderID:         "8b3a6b89697e8ac8f45d964bcc90c7ba41764acd",
 				},
+				EndpointID: "503368",
 			},
 			expectedError: nil,
 			mockSDK: SDK{
@@ -123,7 +124,7 @@ func Test_Deposit(t *testing.T) {
 				CustomerZipCode:     "84280",
 				CustomerPhone:       "+1 420-100-1000",
 				CustomerIP:          "127.0.0.1",
-				CustomerBankCode:    "BBL",
+				CustomerBankCode:    "MBB",
 				RedirectURL:         "https://some.endpoint/redirect",
 				CallbackURL:         "https://some.endpoint/callback",
 				CheckoutURL:         "https://vt.com/",
@@ -135,8 +136,9 @@ func Test_Deposit(t *testing.T) {
 			// --------------------Test Http Error Api response----------------------
 			name: "Deposit Http Request API Error",
 			expectedDepositResult: DepositResult{
-				Code:    "400",
-				Message: "bad request",
+				Code:       "400",
+				Message:    "bad request",
+				EndpointID: "503368",
 			},
 			expectedError: nil,
 			mockSDK: SDK{
@@ -161,7 +163,7 @@ func Test_Deposit(t *testing.T) {
 				CustomerZipCode:     "84280",
 				CustomerPhone:       "+1 420-100-1000",
 				CustomerIP:          "127.0.0.1",
-				CustomerBankCode:    "BBL",
+				CustomerBankCode:    "MBB",
 				RedirectURL:         "https://some.endpoint/redirect",
 				CallbackURL:         "https://some.endpoint/callback",
 				CheckoutURL:         "https://vt.com/",
@@ -199,7 +201,7 @@ func Test_Deposit(t *testing.T) {
 				CustomerZipCode:     "84280",
 				CustomerPhone:       "+1 420-100-1000",
 				CustomerIP:          "127.0.0.1",
-				CustomerBankCode:    "BBL",
+				CustomerBankCode:    "MBB",
 				RedirectURL:         "https://some.endpoint/redirect",
 				CallbackURL:         "https://some.endpoint/callback",
 				CheckoutURL:         "https://vt.com/",
@@ -234,7 +236,7 @@ func Test_Deposit(t *testing.T) {
 				CustomerZipCode:     "84280",
 				CustomerPhone:       "+1 420-100-1000",
 				CustomerIP:          "127.0.0.1",
-				CustomerBankCode:    "BBL",
+				CustomerBankCode:    "MBB",
 				RedirectURL:         "https://some.endpoint/redirect",
 				CallbackURL:         "https://some.endpoint/callback",
 				CheckoutURL:         "https://vt.com/",
@@ -318,7 +320,7 @@ func TestDepositResult_SetMockResponse(t *testing.T) {
 		CustomerZipCode:     "84280",
 		CustomerPhone:       "+1 420-100-1000",
 		CustomerIP:          "127.0.0.1",
-		CustomerBankCode:    "BBL",
+		CustomerBankCode:    "MBB",
 		RedirectURL:         "https://some.endpoint/redirect",
 		CallbackURL:         "https://some.endpoint/callback",
 		CheckoutURL:         "https://some.endpoint/checkout",
@@ -345,7 +347,7 @@ func TestDepositResult_SetMockResponse(t *testing.T) {
 		CustomerZipCode:     "84280",
 		CustomerPhone:       "+1 420-100-1000",
 		CustomerIP:          "127.0.0.1",
-		CustomerBankCode:    "BBL",
+		CustomerBankCode:    "MBB",
 		RedirectURL:         "https://some.endpoint/redirect",
 		CallbackURL:         "https://some.endpoint/callback",
 		CheckoutURL:         "https://some.endpoint/checkout",
@@ -377,7 +379,7 @@ func TestDeposit_Validate(t *testing.T) {
 				CustomerZipCode:     "84280",
 				CustomerPhone:       "+1 420-100-1000",
 				CustomerIP:          "127.0.0.1",
-				CustomerBankCode:    "BBL",
+				CustomerBankCode:    "MBB",
 				RedirectURL:         "https://some.endpoint/redirect",
 				CallbackURL:         "https://some.endpoint/callback",
 				CheckoutURL:         "https://some.endpoint/checkout",
@@ -0,0 +1,84 @@
+package zota
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/url"
+)
+
+// DryRunMode represents which requests are not sent to Zota API
+// requests not sent are validated, routed, signed and logged
+// and return a synthetic result with DryRun set
+type DryRunMode int
+
+const (
+	// DryRunOff sends every request
+	DryRunOff DryRunMode = iota
+	// DryRunWrites does not send deposits and payouts, order status and orders report are sent
+	DryRunWrites
+	// DryRunAll does not send any request
+	DryRunAll
+)
+
+// dryRunKey is the context key of the DryRunMode
+type dryRunKey struct{}
+
+// WithDryRun returns a context overriding SDK.DryRun for the requests made with it
+func WithDryRun(ctx context.Context, mode DryRunMode) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, mode)
+}
+
+// dryRun reports whether the request of the operation is not sent
+// the mode of ctx overrides the mode of the SDK
+func (s *SDK) dryRun(ctx context.Context, op Operation) bool {
+	mode := s.DryRun
+	if m, ok := ctx.Value(dryRunKey{}).(DryRunMode); ok {
+		mode = m
+	}
+	switch mode {
+	case DryRunAll:
+		return true
+	case DryRunWrites:
+		return op == OperationDeposit || op == OperationPayout
+	}
+	return false
+}
+
+// dryRunResponse logs the request and returns its synthetic response
+// the body of the request is never logged, it may contain card data
+func (s *SDK) dryRunResponse(r PreparedRequest) PreparedResponse {
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		u = &url.URL{}
+	}
+	if s.OnDryRun != nil {
+		logged := r
+		logged.Body = nil
+		s.OnDryRun(logged)
+	} else {
+		log.Printf("zota dry-run %v %v %v merchantOrderID:%v", r.Operation, r.Method, u.Path, r.MerchantOrderID)
+	}
+
+	resp := PreparedResponse{
+		Operation:  r.Operation,
+		EndpointID: r.EndpointID,
+		StatusCode: 200,
+		DryRun:     true,
+	}
+	if r.Operation == OperationOrdersReport {
+		//an empty csv report
+		return resp
+	}
+
+	data := map[string]string{"merchantOrderID": r.MerchantOrderID, "orderID": "dry-run-" + r.MerchantOrderID}
+	if r.Operation == OperationOrderStatus {
+		data["orderID"] = u.Query().Get("orderID")
+	}
+	resp.Body, _ = json.Marshal(map[string]interface{}{
+		"code":    "200",
+		"message": "dry-run",
+		"data":    data,
+	})
+	return resp
+}
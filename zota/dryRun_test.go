@@ -0,0 +1,107 @@
+package zota
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSDK_DryRun(t *testing.T) {
+	status := &ClientMockStatus{StatusCode: 200}
+	sdk := testPollSDK(status)
+	sdk.DryRun = DryRunWrites
+
+	var logged []PreparedRequest
+	sdk.OnDryRun = func(r PreparedRequest) {
+		logged = append(logged, r)
+	}
+
+	res, err := sdk.Deposit(testDepositOrder())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, DepositResult{
+		Code:       "200",
+		Message:    "dry-run",
+		Data:       DepositResultData{MerchantOrderID: "134", OrderID: "dry-run-134"},
+		EndpointID: "503368",
+		DryRun:     true,
+	}, res)
+
+	payout, err := sdk.Payout(brazilPayoutOrder())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, payout.DryRun)
+	assert.Equal(t, "200", payout.Code)
+
+	//card deposits are not sent and the card is wiped
+	order := sensitiveOrder()
+	cc, err := sdk.DepositCC(order)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, cc.DryRun)
+	assert.Equal(t, true, order.Card.IsWiped())
+
+	assert.Equal(t, 0, status.calls)
+	assert.Equal(t, 3, len(logged))
+	assert.Equal(t, OperationDeposit, logged[2].Operation)
+	assert.Nil(t, logged[2].Body)
+	assert.NotEqual(t, "", logged[2].Signature)
+
+	//reads are sent with DryRunWrites
+	sdk.HttpClient = &ClientMockSequence{responses: []string{statusResponse(StatusApproved)}}
+	st, err := sdk.OrderStatus(OrderStatus{MerchantOrderID: "2", OrderID: "1"})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, false, st.DryRun)
+	assert.Equal(t, StatusApproved, st.Status)
+
+	//the context overrides the SDK mode
+	sdk.HttpClient = status
+	res, err = sdk.DepositContext(WithDryRun(context.Background(), DryRunOff), testDepositOrder())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, false, res.DryRun)
+	assert.Equal(t, 1, status.calls)
+}
+
+func TestSDK_DryRunAll(t *testing.T) {
+	status := &ClientMockStatus{StatusCode: 200}
+	sdk := testPollSDK(status)
+
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	ctx := WithDryRun(context.Background(), DryRunAll)
+	st, err := sdk.OrderStatusContext(ctx, OrderStatus{MerchantOrderID: "m-1", OrderID: "1"})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, true, st.DryRun)
+	assert.Equal(t, "1", st.OrderID)
+	assert.Equal(t, "m-1", st.MerchantOrderID)
+	assert.Equal(t, OrderStatusCode(""), st.Status)
+
+	report, err := sdk.OrdersReportContext(ctx, OrdersReport{DateType: "created", FromDate: "2021-01-01", ToDate: "2021-01-02"})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, OrdersReportResult{Code: "200", DryRun: true}, report)
+
+	_, err = sdk.DepositContext(ctx, testDepositOrder())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, status.calls)
+
+	assert.Contains(t, buf.String(), "zota dry-run order-status GET /api/v1/query/order-status/ merchantOrderID:m-1")
+	assert.Contains(t, buf.String(), "zota dry-run deposit POST /api/v1/deposit/request/503368/ merchantOrderID:134")
+	assert.NotContains(t, buf.String(), "signature")
+	assert.NotContains(t, buf.String(), "customer@email-address.com")
+}
+
+func TestPayoutOutbox_DryRun(t *testing.T) {
+	sdk := testPollSDK(&ClientMockStatus{StatusCode: 200})
+	sdk.DryRun = DryRunWrites
+	sdk.OnDryRun = func(r PreparedRequest) {}
+
+	o, _ := NewPayoutOutbox(sdk, NewMemoryOutboxStore(), PayoutOutboxConfig{})
+	o.Enqueue(outboxPayout("p-1"))
+	assert.Equal(t, nil, o.SendPending(context.Background()))
+	item, _, _ := o.Get("p-1")
+	assert.Equal(t, OutboxParked, item.State)
+	assert.Equal(t, "dry-run", item.Error)
+}
@@ -0,0 +1,107 @@
+package zota
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PaymentMethod represents the payment method of an order
+type PaymentMethod string
+
+const (
+	PaymentMethodBank PaymentMethod = "bank"
+	PaymentMethodCard PaymentMethod = "card"
+)
+
+// EndpointRoute describes the order an EndpointID is selected for
+type EndpointRoute struct {
+	Operation     Operation
+	PaymentMethod PaymentMethod
+	Currency      string
+	Country       string
+}
+
+// EndpointRouter selects the EndpointID used for an order
+// an empty EndpointID falls back to SDK.EndpointID
+type EndpointRouter interface {
+	Route(r EndpointRoute) string
+}
+
+// EndpointRouterFunc is an EndpointRouter implemented by a function
+type EndpointRouterFunc func(r EndpointRoute) string
+
+// Route implements EndpointRouter
+func (f EndpointRouterFunc) Route(r EndpointRoute) string {
+	return f(r)
+}
+
+// EndpointRule maps the orders matching the rule to an EndpointID
+// empty fields match any value
+type EndpointRule struct {
+	Operation     Operation
+	PaymentMethod PaymentMethod
+	Currency      string
+	Country       string
+	EndpointID    string
+}
+
+// EndpointTable is an EndpointRouter returning the EndpointID
+// of the first EndpointRule matching the order
+type EndpointTable []EndpointRule
+
+// Route implements EndpointRouter
+func (t EndpointTable) Route(r EndpointRoute) string {
+	for _, rule := range t {
+		if rule.Operation != "" && rule.Operation != r.Operation {
+			continue
+		}
+		if rule.PaymentMethod != "" && rule.PaymentMethod != r.PaymentMethod {
+			continue
+		}
+		if rule.Currency != "" && !strings.EqualFold(rule.Currency, r.Currency) {
+			continue
+		}
+		if rule.Country != "" && !strings.EqualFold(rule.Country, r.Country) {
+			continue
+		}
+		return rule.EndpointID
+	}
+	return ""
+}
+
+// Endpoints returns all the EndpointIDs of the table
+func (t EndpointTable) Endpoints() []string {
+	var endpoints []string
+	for _, rule := range t {
+		if !containsFold(endpoints, rule.EndpointID) {
+			endpoints = append(endpoints, rule.EndpointID)
+		}
+	}
+	return endpoints
+}
+
+// endpoint returns the EndpointID for the route
+// using the EndpointRouter and falling back to SDK.EndpointID
+func (s *SDK) endpoint(r EndpointRoute) (string, error) {
+	endpointID := ""
+	if s.EndpointRouter != nil {
+		endpointID = s.EndpointRouter.Route(r)
+	}
+	if endpointID == "" {
+		endpointID = s.EndpointID
+	}
+	if endpointID == "" {
+		return "", fmt.Errorf("no EndpointID for %v %v %v %v", r.Operation, r.PaymentMethod, r.Currency, r.Country)
+	}
+	return endpointID, nil
+}
+
+// acceptsEndpoint reports whether a callback for the EndpointID is accepted
+// all endpoints are accepted unless the EndpointRouter can list its endpoints
+func (s *SDK) acceptsEndpoint(endpointID string) bool {
+	lister, ok := s.EndpointRouter.(interface{ Endpoints() []string })
+	if !ok {
+		return true
+	}
+	return endpointID == s.EndpointID || containsFold(lister.Endpoints(), endpointID)
+}
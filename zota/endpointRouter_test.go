@@ -0,0 +1,142 @@
+package zota
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ClientMockCaptureURL capture the request url
+// implement httpClient interface
+type ClientMockCaptureURL struct {
+	url  string
+	body string
+}
+
+func (c *ClientMockCaptureURL) Do(req *http.Request) (*http.Response, error) {
+	c.url = req.URL.String()
+	b, _ := ioutil.ReadAll(req.Body)
+	c.body = string(b)
+	return &http.Response{
+		StatusCode: 200,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(`{"code":"200","data":{"merchantOrderID":"1","orderID":"2"}}`))),
+	}, nil
+}
+
+var testEndpointTable = EndpointTable{
+	{Operation: OperationPayout, Currency: "THB", EndpointID: "400001"},
+	{Operation: OperationDeposit, PaymentMethod: PaymentMethodCard, EndpointID: "400002"},
+	{Currency: "MYR", Country: "MY", EndpointID: "400003"},
+	{Currency: "MYR", EndpointID: "400004"},
+}
+
+func TestEndpointTable_Route(t *testing.T) {
+	tests := map[string]EndpointRoute{
+		"400001": {Operation: OperationPayout, PaymentMethod: PaymentMethodBank, Currency: "thb"},
+		"400002": {Operation: OperationDeposit, PaymentMethod: PaymentMethodCard, Currency: "MYR", Country: "MY"},
+		"400003": {Operation: OperationDeposit, PaymentMethod: PaymentMethodBank, Currency: "MYR", Country: "MY"},
+		"400004": {Operation: OperationPayout, PaymentMethod: PaymentMethodBank, Currency: "MYR", Country: "SG"},
+		"":       {Operation: OperationDeposit, PaymentMethod: PaymentMethodBank, Currency: "THB"},
+	}
+	for expected, route := range tests {
+		assert.Equal(t, expected, testEndpointTable.Route(route))
+	}
+	assert.Equal(t, []string{"400001", "400002", "400003", "400004"}, testEndpointTable.Endpoints())
+}
+
+func TestSDK_Endpoint(t *testing.T) {
+	sdk := SDK{EndpointID: "503368"}
+	endpointID, err := sdk.endpoint(EndpointRoute{Operation: OperationDeposit})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "503368", endpointID)
+
+	sdk.EndpointRouter = EndpointRouterFunc(func(r EndpointRoute) string {
+		if r.Currency == "USD" {
+			return "500001"
+		}
+		return ""
+	})
+	endpointID, err = sdk.endpoint(EndpointRoute{Operation: OperationDeposit, Currency: "USD"})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "500001", endpointID)
+
+	endpointID, err = sdk.endpoint(EndpointRoute{Operation: OperationDeposit, Currency: "EUR"})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "503368", endpointID)
+
+	sdk.EndpointID = ""
+	_, err = sdk.endpoint(EndpointRoute{Operation: OperationPayout, PaymentMethod: PaymentMethodBank, Currency: "EUR", Country: "DE"})
+	assert.Equal(t, fmt.Errorf("no EndpointID for payout bank EUR DE"), err)
+}
+
+func Test_DepositWithEndpointRouter(t *testing.T) {
+	client := ClientMockCaptureURL{}
+	sdk := SDK{
+		MerchantID:        "API_MERCHANT_ID",
+		MerchantSecretKey: "API_MERCHANT_SECRET_KEY",
+		ApiBaseURL:        SANDBOX,
+		HttpClient:        &client,
+		EndpointRouter:    testEndpointTable,
+	}
+
+	order := DepositOrder{
+		MerchantOrderID:     "134",
+		MerchantOrderDesc:   "Test order description",
+		OrderAmount:         "500",
+		OrderCurrency:       "MYR",
+		CustomerEmail:       "customer@email-address.com",
+		CustomerLastName:    "Doe",
+		CustomerAddress:     "The Swan, Jungle St. 108",
+		CustomerCountryCode: "MY",
+		CustomerCity:        "Kuala Lumpur",
+		CustomerZipCode:     "84280",
+		CustomerPhone:       "+60 420-100-1000",
+		CustomerIP:          "127.0.0.1",
+		RedirectURL:         "https://some.endpoint/redirect",
+		CheckoutURL:         "https://some.endpoint/checkout",
+	}
+	assert.Equal(t, nil, sdk.validate())
+	res, err := sdk.Deposit(order)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "400003", res.EndpointID)
+	assert.Equal(t, SANDBOX+"/api/v1/deposit/request/400003/", client.url)
+	assert.Contains(t, client.body, sdk.sign("400003", order.MerchantOrderID, order.OrderAmount, order.CustomerEmail))
+
+	//no route and no default EndpointID
+	order.OrderCurrency = "USD"
+	_, err = sdk.Deposit(order)
+	assert.Equal(t, fmt.Errorf("no EndpointID for deposit bank USD MY"), err)
+}
+
+func TestSDK_CallbackWithEndpointRouter(t *testing.T) {
+	sdk := SDK{
+		MerchantID:        "API_MERCHANT_ID",
+		MerchantSecretKey: "API_MERCHANT_SECRET_KEY",
+		EndpointID:        "503368",
+		ApiBaseURL:        SANDBOX,
+		EndpointRouter:    testEndpointTable,
+	}
+
+	callbackFor := func(endpointID string) []byte {
+		sign := sdk.sign(endpointID + "1" + "2" + "APPROVED" + "10.00" + "a@b.c")
+		return []byte(fmt.Sprintf(`{"endpointID":"%v","orderID":"1","merchantOrderID":"2","status":"APPROVED","amount":"10.00","customerEmail":"a@b.c","signature":"%v"}`, endpointID, sign))
+	}
+
+	for _, endpointID := range []string{"503368", "400001", "400004"} {
+		c, err := sdk.Callback(callbackFor(endpointID))
+		assert.Equal(t, nil, err)
+		assert.Equal(t, endpointID, c.EndpointID)
+	}
+
+	_, err := sdk.Callback(callbackFor("999999"))
+	assert.Equal(t, fmt.Errorf("unexpected endpointID"), err)
+
+	//routers which can not list their endpoints accept any endpoint
+	sdk.EndpointRouter = EndpointRouterFunc(func(r EndpointRoute) string { return "" })
+	_, err = sdk.Callback(callbackFor("999999"))
+	assert.Equal(t, nil, err)
+}
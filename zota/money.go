@@ -0,0 +1,182 @@
+package zota
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// moneyDecimals is the number of decimal places of the Zota API amounts
+const moneyDecimals = 8
+
+// moneyScale is 10^moneyDecimals
+const moneyScale = 100000000
+
+// Money represents an amount as a fixed point number with 8 decimal places
+// as returned by Zota API, e.g. "500.00000000"
+// the zero value is 0
+type Money struct {
+	units int64
+}
+
+// ParseMoney parses a decimal amount with up to 8 decimal places
+// an empty string is 0
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Money{}, nil
+	}
+
+	neg := false
+	digits := s
+	switch digits[0] {
+	case '-':
+		neg = true
+		digits = digits[1:]
+	case '+':
+		digits = digits[1:]
+	}
+
+	whole, frac, _ := strings.Cut(digits, ".")
+	if whole == "" && frac == "" {
+		return Money{}, fmt.Errorf("invalid amount %q", s)
+	}
+	if len(frac) > moneyDecimals {
+		//Zota amounts have 8 decimal places, the extra digits must be zeros
+		if strings.Trim(frac[moneyDecimals:], "0") != "" {
+			return Money{}, fmt.Errorf("invalid amount %q", s)
+		}
+		frac = frac[:moneyDecimals]
+	}
+	if !onlyDigits([]byte(whole)) || !onlyDigits([]byte(frac)) {
+		return Money{}, fmt.Errorf("invalid amount %q", s)
+	}
+	frac += strings.Repeat("0", moneyDecimals-len(frac))
+
+	var units int64
+	if whole != "" {
+		w, err := strconv.ParseInt(whole, 10, 64)
+		if err != nil || w > (1<<63-1)/moneyScale {
+			return Money{}, fmt.Errorf("invalid amount %q", s)
+		}
+		units = w * moneyScale
+	}
+	f, _ := strconv.ParseInt(frac, 10, 64)
+	units += f
+
+	if neg {
+		units = -units
+	}
+	return Money{units: units}, nil
+}
+
+// MustParseMoney is ParseMoney panicking on error, for constants
+func MustParseMoney(s string) Money {
+	m, err := ParseMoney(s)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// MoneyFromUnits returns the amount of units of 10^-8
+func MoneyFromUnits(units int64) Money {
+	return Money{units: units}
+}
+
+// Units returns the amount in units of 10^-8
+func (m Money) Units() int64 {
+	return m.units
+}
+
+// Add returns m + o
+func (m Money) Add(o Money) Money {
+	return Money{units: m.units + o.units}
+}
+
+// Sub returns m - o
+func (m Money) Sub(o Money) Money {
+	return Money{units: m.units - o.units}
+}
+
+// Cmp returns -1, 0 or 1 if m is less than, equal to or greater than o
+func (m Money) Cmp(o Money) int {
+	switch {
+	case m.units < o.units:
+		return -1
+	case m.units > o.units:
+		return 1
+	}
+	return 0
+}
+
+// IsZero reports whether the amount is 0
+func (m Money) IsZero() bool {
+	return m.units == 0
+}
+
+// Float64 returns the amount as a float64, for display only
+func (m Money) Float64() float64 {
+	return float64(m.units) / moneyScale
+}
+
+// String returns the amount with 8 decimal places, e.g. "500.00000000"
+func (m Money) String() string {
+	return m.Format(moneyDecimals)
+}
+
+// Format returns the amount rounded half away from zero to the decimal places (0..8)
+func (m Money) Format(decimals int) string {
+	if decimals < 0 {
+		decimals = 0
+	}
+	if decimals > moneyDecimals {
+		decimals = moneyDecimals
+	}
+
+	units := m.units
+	sign := ""
+	if units < 0 {
+		sign = "-"
+		units = -units
+	}
+	step := int64(1)
+	for i := decimals; i < moneyDecimals; i++ {
+		step *= 10
+	}
+	units = (units + step/2) / step * step
+
+	whole := units / moneyScale
+	if decimals == 0 {
+		return fmt.Sprintf("%v%d", sign, whole)
+	}
+	frac := fmt.Sprintf("%08d", units%moneyScale)[:decimals]
+	return fmt.Sprintf("%v%d.%v", sign, whole, frac)
+}
+
+// MarshalJSON encodes the amount as a json string with 8 decimal places
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON decodes the amount from a json string or number
+func (m *Money) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if len(b) > 0 && b[0] == '"' {
+		err := json.Unmarshal(b, &s)
+		if err != nil {
+			return err
+		}
+	}
+	if s == "null" {
+		*m = Money{}
+		return nil
+	}
+	v, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = v
+	return nil
+}
@@ -0,0 +1,69 @@
+package zota
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := map[string]int64{
+		"":                0,
+		"0":               0,
+		"500.00000000":    50000000000,
+		"500":             50000000000,
+		"12.5":            1250000000,
+		"-0.01":           -1000000,
+		"+3.":             300000000,
+		".5":              50000000,
+		"1.1234567800":    112345678,
+		" 7.00000001 ":    700000001,
+		"92233720368.547": 9223372036854700000,
+	}
+	for s, units := range tests {
+		m, err := ParseMoney(s)
+		assert.Equal(t, nil, err, s)
+		assert.Equal(t, units, m.Units(), s)
+	}
+
+	for _, s := range []string{".", "-", "abc", "1.2.3", "1,5", "1.123456789", "1e5", "92233720369"} {
+		_, err := ParseMoney(s)
+		assert.Equal(t, fmt.Errorf("invalid amount %q", s), err, s)
+	}
+}
+
+func TestMoney_Format(t *testing.T) {
+	m := MustParseMoney("1234.56789")
+	assert.Equal(t, "1234.56789000", m.String())
+	assert.Equal(t, "1234.57", m.Format(2))
+	assert.Equal(t, "1235", m.Format(0))
+	assert.Equal(t, "-0.01", MustParseMoney("-0.005").Format(2))
+	assert.Equal(t, "0.00000000", Money{}.String())
+	assert.Equal(t, 1234.56789, m.Float64())
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	a := MustParseMoney("10.10")
+	b := MustParseMoney("0.20")
+	assert.Equal(t, MustParseMoney("10.30"), a.Add(b))
+	assert.Equal(t, MustParseMoney("9.90"), a.Sub(b))
+	assert.Equal(t, 1, a.Cmp(b))
+	assert.Equal(t, -1, b.Cmp(a))
+	assert.Equal(t, 0, a.Cmp(MoneyFromUnits(1010000000)))
+	assert.True(t, a.Sub(a).IsZero())
+}
+
+func TestMoney_JSON(t *testing.T) {
+	b, err := json.Marshal(struct{ Amount Money }{MustParseMoney("5")})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"Amount":"5.00000000"}`, string(b))
+
+	var v struct{ A, B, C Money }
+	assert.Equal(t, nil, json.Unmarshal([]byte(`{"A":"1.5","B":2.25,"C":null}`), &v))
+	assert.Equal(t, MustParseMoney("1.5"), v.A)
+	assert.Equal(t, MustParseMoney("2.25"), v.B)
+	assert.True(t, v.C.IsZero())
+	assert.NotNil(t, json.Unmarshal([]byte(`{"A":"x"}`), &v))
+}
@@ -1,7 +1,7 @@
 package zota
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -25,20 +25,23 @@ type OrderStatusResult struct {
 	Code                  string `json:"code"`
 	OrderStatusResultData `json:"data"`
 	Message               string `json:"message"`
+
+	//DryRun is set if the request was not sent, see DryRunMode
+	DryRun bool `json:"-"`
 }
 
 type OrderStatusResultData struct {
-	Type                  string `json:"type"`
-	Status                string `json:"status"`
-	ErrorMessage          string `json:"errorMessage"`
-	EndpointID            string `json:"endpointID"`
-	ExternalTransactionID string `json:"externalTransactionID"`
-	OrderID               string `json:"orderID"`
-	MerchantOrderID       string `json:"merchantOrderID"`
-	Amount                string `json:"amount"`
-	Currency              string `json:"currency"`
-	CustomerEmail         string `json:"customerEmail"`
-	CustomParam           string `json:"customParam"`
+	Type                  string          `json:"type"`
+	Status                OrderStatusCode `json:"status"`
+	ErrorMessage          string          `json:"errorMessage"`
+	EndpointID            string          `json:"endpointID"`
+	ExternalTransactionID string          `json:"externalTransactionID"`
+	OrderID               string          `json:"orderID"`
+	MerchantOrderID       string          `json:"merchantOrderID"`
+	Amount                string          `json:"amount"`
+	Currency              string          `json:"currency"`
+	CustomerEmail         string          `json:"customerEmail"`
+	CustomParam           string          `json:"customParam"`
 	ExtraData             struct {
 		Dcc              bool   `json:"dcc"`
 		SelectedBankCode string `json:"selectedBankCode"`
@@ -58,24 +61,19 @@ var mockedOrderStatusResult *OrderStatusResult
 // generate sign and
 // init order status request to Zota API
 func (s *SDK) OrderStatus(d OrderStatus) (res OrderStatusResult, err error) {
+	return s.OrderStatusContext(context.Background(), d)
+}
 
-	//validate that SDK is properly initialized
-	err = s.validate()
-	if err != nil {
-		return
-	}
+// OrderStatusContext is OrderStatus with a context
+// the request is cancelled when ctx is done
+func (s *SDK) OrderStatusContext(ctx context.Context, d OrderStatus) (res OrderStatusResult, err error) {
 
-	//validate that OrderStatus is properly initialized
-	err = d.validate()
+	//validate and sign the OrderStatus
+	r, err := s.PrepareOrderStatus(d)
 	if err != nil {
 		return
 	}
 
-	d.MerchantID = s.MerchantID
-
-	//set current timestamp
-	d.Timestamp = strconv.FormatInt(time.Now().Unix(), 10)
-
 	//if mockedOrderStatusResult is set return it as response
 	//only for testing
 	if mockedOrderStatusResult != nil {
@@ -84,25 +82,45 @@ func (s *SDK) OrderStatus(d OrderStatus) (res OrderStatusResult, err error) {
 		return
 	}
 
-	//generate signature
-	d.Signature = s.sign(d.MerchantID, d.MerchantOrderID, d.OrderID, d.Timestamp)
+	resp, err := s.Send(ctx, r)
+	if err != nil {
+		return
+	}
+	return resp.OrderStatusResult()
+}
 
-	v, err := query.Values(d)
+// PrepareOrderStatus init validation of the SDK struct and the OrderStatus
+// generate sign and
+// returns the order status request to be sent later with Send
+// the request is signed with the current timestamp
+func (s *SDK) PrepareOrderStatus(d OrderStatus) (r PreparedRequest, err error) {
+
+	//validate that SDK is properly initialized
+	err = s.validate()
 	if err != nil {
 		return
 	}
 
-	_, body, err := s.httpDo(http.MethodGet, fmt.Sprintf("%v/api/v1/query/order-status/?%v", s.ApiBaseURL, v.Encode()), []byte(""))
+	//validate that OrderStatus is properly initialized
+	err = d.validate()
 	if err != nil {
 		return
 	}
 
-	err = json.Unmarshal(body, &res)
+	d.MerchantID = s.MerchantID
+
+	//set current timestamp
+	d.Timestamp = strconv.FormatInt(time.Now().Unix(), 10)
+
+	//generate signature
+	d.Signature = s.sign(d.MerchantID, d.MerchantOrderID, d.OrderID, d.Timestamp)
+
+	v, err := query.Values(d)
 	if err != nil {
-		err = fmt.Errorf("json Unmarshal err:%v", err)
 		return
 	}
 
+	r = newPreparedRequest(OperationOrderStatus, "", d.MerchantOrderID, http.MethodGet, fmt.Sprintf("%v/api/v1/query/order-status/?%v", s.ApiBaseURL, v.Encode()), nil, d.Signature)
 	return
 }
 
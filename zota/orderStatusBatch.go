@@ -0,0 +1,145 @@
+package zota
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// OrderRef identifies an order for OrderStatusBatch
+type OrderRef struct {
+	MerchantOrderID string
+	OrderID         string
+}
+
+// BatchOptions configures OrderStatusBatch
+// zero values are replaced by the defaults
+type BatchOptions struct {
+	// Concurrency is the maximum number of requests in flight, 4 by default
+	Concurrency int
+	// RateLimit is the maximum number of requests per second, 5 by default
+	RateLimit float64
+}
+
+// OrderStatusBatchResult is the result of the lookup of a single order
+// Index is the position of the order in the input
+type OrderStatusBatchResult struct {
+	Index  int
+	Ref    OrderRef
+	Result OrderStatusResult
+	Err    error
+}
+
+// OrderStatusBatchSummary counts the batch results by status
+type OrderStatusBatchSummary struct {
+	Total    int
+	Errors   int
+	ByStatus map[OrderStatusCode]int
+}
+
+// Add counts a result in the summary
+func (sum *OrderStatusBatchSummary) Add(r OrderStatusBatchResult) {
+	if sum.ByStatus == nil {
+		sum.ByStatus = map[OrderStatusCode]int{}
+	}
+	sum.Total++
+	if r.Err != nil {
+		sum.Errors++
+		return
+	}
+	sum.ByStatus[r.Result.Status]++
+}
+
+// OrderStatusBatch looks up the status of many orders
+// with bounded concurrency and rate limit
+// returns the results in input order and the summary counts
+// orders not looked up before ctx is done have ctx.Err() as error
+func (s *SDK) OrderStatusBatch(ctx context.Context, refs []OrderRef, opts BatchOptions) ([]OrderStatusBatchResult, OrderStatusBatchSummary) {
+	results := make([]OrderStatusBatchResult, len(refs))
+	summary := OrderStatusBatchSummary{ByStatus: map[OrderStatusCode]int{}}
+	done := make([]bool, len(refs))
+	for r := range s.OrderStatusBatchStream(ctx, refs, opts) {
+		results[r.Index] = r
+		done[r.Index] = true
+		summary.Add(r)
+	}
+	//the stream drops the results once ctx is done
+	for i, ok := range done {
+		if !ok {
+			results[i] = OrderStatusBatchResult{Index: i, Ref: refs[i], Err: ctx.Err()}
+			summary.Add(results[i])
+		}
+	}
+	return results, summary
+}
+
+// OrderStatusBatchStream is OrderStatusBatch streaming the results
+// through a channel as they complete
+// the channel is closed once every order has a result or ctx is done
+// callers must drain the channel or cancel ctx, the workers block until then
+// once ctx is done the results not yet sent may be dropped
+// the orders have the error creating the http client as error if it fails
+// the requests have the PriorityBatch unless ctx carries a Priority
+func (s *SDK) OrderStatusBatchStream(ctx context.Context, refs []OrderRef, opts BatchOptions) <-chan OrderStatusBatchResult {
+	ctx = withDefaultPriority(ctx, PriorityBatch)
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+	if opts.RateLimit <= 0 {
+		opts.RateLimit = 5
+	}
+
+	//every order has the error creating the http client, no request is sent
+	err := s.initHttpClient()
+	if err != nil {
+		out := make(chan OrderStatusBatchResult, len(refs))
+		for i, ref := range refs {
+			out <- OrderStatusBatchResult{Index: i, Ref: ref, Err: err}
+		}
+		close(out)
+		return out
+	}
+
+	out := make(chan OrderStatusBatchResult, opts.Concurrency)
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for i := 0; i < opts.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				r := OrderStatusBatchResult{Index: i, Ref: refs[i]}
+				if ctx.Err() != nil {
+					r.Err = ctx.Err()
+				} else {
+					r.Result, r.Err = s.OrderStatusContext(ctx, OrderStatus{MerchantOrderID: refs[i].MerchantOrderID, OrderID: refs[i].OrderID})
+				}
+				select {
+				case out <- r:
+				case <-ctx.Done():
+				}
+			}
+		}()
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / opts.RateLimit))
+		defer ticker.Stop()
+		for i := range refs {
+			//the first request is sent right away, the next ones wait for the ticker
+			if i > 0 && ctx.Err() == nil {
+				select {
+				case <-ticker.C:
+				case <-ctx.Done():
+				}
+			}
+			jobs <- i
+		}
+		close(jobs)
+		wg.Wait()
+		close(out)
+	}()
+
+	return out
+}
@@ -0,0 +1,131 @@
+package zota
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSDK_OrderStatusBatch(t *testing.T) {
+	client := &ClientMockByOrder{
+		statuses: map[string][]OrderStatusCode{
+			"1": {StatusApproved},
+			"2": {StatusPending},
+			"3": {StatusApproved},
+			"4": {StatusDeclined},
+		},
+		calls: map[string]int{},
+	}
+	refs := []OrderRef{
+		{MerchantOrderID: "m-1", OrderID: "1"},
+		{MerchantOrderID: "m-2", OrderID: "2"},
+		{MerchantOrderID: "m-3", OrderID: "3"},
+		{MerchantOrderID: "m-4", OrderID: "4"},
+		{MerchantOrderID: "m-5", OrderID: ""},
+	}
+
+	results, summary := testPollSDK(client).OrderStatusBatch(context.Background(), refs, BatchOptions{Concurrency: 2, RateLimit: 1000})
+	assert.Equal(t, 5, len(results))
+	for i, r := range results {
+		assert.Equal(t, i, r.Index)
+		assert.Equal(t, refs[i], r.Ref)
+	}
+	assert.Equal(t, StatusApproved, results[0].Result.Status)
+	assert.Equal(t, StatusPending, results[1].Result.Status)
+	assert.Equal(t, StatusDeclined, results[3].Result.Status)
+	assert.Equal(t, fmt.Errorf("OrderID is required"), results[4].Err)
+
+	assert.Equal(t, OrderStatusBatchSummary{
+		Total:    5,
+		Errors:   1,
+		ByStatus: map[OrderStatusCode]int{StatusApproved: 2, StatusPending: 1, StatusDeclined: 1},
+	}, summary)
+}
+
+func TestSDK_OrderStatusBatchRateLimit(t *testing.T) {
+	client := &ClientMockByOrder{statuses: map[string][]OrderStatusCode{"1": {StatusApproved}}, calls: map[string]int{}}
+	refs := make([]OrderRef, 5)
+	for i := range refs {
+		refs[i] = OrderRef{MerchantOrderID: "m-1", OrderID: "1"}
+	}
+
+	start := time.Now()
+	var count int
+	for r := range testPollSDK(client).OrderStatusBatchStream(context.Background(), refs, BatchOptions{Concurrency: 5, RateLimit: 100}) {
+		assert.Equal(t, nil, r.Err)
+		count++
+	}
+	assert.Equal(t, 5, count)
+	assert.True(t, time.Since(start) >= 40*time.Millisecond)
+}
+
+func TestSDK_OrderStatusBatchCancel(t *testing.T) {
+	client := &ClientMockByOrder{statuses: map[string][]OrderStatusCode{"1": {StatusApproved}}, calls: map[string]int{}}
+	refs := make([]OrderRef, 10)
+	for i := range refs {
+		refs[i] = OrderRef{MerchantOrderID: "m-1", OrderID: "1"}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, summary := testPollSDK(client).OrderStatusBatch(ctx, refs, BatchOptions{})
+	assert.Equal(t, 10, len(results))
+	assert.Equal(t, 10, summary.Errors)
+	assert.Equal(t, context.Canceled, results[9].Err)
+}
+
+func TestSDK_OrderStatusBatchInitHttpClient(t *testing.T) {
+	//the client is created once before the workers share the SDK, run with -race
+	//the proxy refuses the connections so no request reaches the network
+	s := testPollSDK(nil)
+	s.Transport = &TransportConfig{Proxy: &url.URL{Scheme: "http", Host: "127.0.0.1:1"}}
+	refs := make([]OrderRef, 8)
+	for i := range refs {
+		refs[i] = OrderRef{MerchantOrderID: "m-1", OrderID: "1"}
+	}
+
+	_, summary := s.OrderStatusBatch(context.Background(), refs, BatchOptions{Concurrency: 8, RateLimit: 1000})
+	assert.Equal(t, 8, summary.Errors)
+	assert.NotEqual(t, nil, s.HttpClient)
+}
+
+func TestSDK_OrderStatusBatchTransportError(t *testing.T) {
+	s := testPollSDK(nil)
+	s.Transport = &TransportConfig{RootCAs: []byte("not a certificate")}
+	refs := []OrderRef{{MerchantOrderID: "m-1", OrderID: "1"}, {MerchantOrderID: "m-2", OrderID: "2"}}
+
+	results, summary := s.OrderStatusBatch(context.Background(), refs, BatchOptions{})
+	assert.Equal(t, 2, summary.Errors)
+	for i, r := range results {
+		assert.Equal(t, i, r.Index)
+		assert.Equal(t, refs[i], r.Ref)
+		assert.Equal(t, "RootCAs contains no PEM certificate", r.Err.Error())
+	}
+}
+
+func TestSDK_OrderStatusBatchStreamAbandoned(t *testing.T) {
+	client := &ClientMockByOrder{statuses: map[string][]OrderStatusCode{"1": {StatusApproved}}, calls: map[string]int{}}
+	refs := make([]OrderRef, 10)
+	for i := range refs {
+		refs[i] = OrderRef{MerchantOrderID: "m-1", OrderID: "1"}
+	}
+
+	//the consumer reads one result and stops, cancelling ctx releases the blocked workers
+	ctx, cancel := context.WithCancel(context.Background())
+	out := testPollSDK(client).OrderStatusBatchStream(ctx, refs, BatchOptions{Concurrency: 1, RateLimit: 1000})
+	<-out
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+
+	//only the buffered result is left, the others were dropped and the channel closed
+	received := 0
+	for range out {
+		received++
+	}
+	assert.Equal(t, 1, received)
+}
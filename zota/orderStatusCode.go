@@ -0,0 +1,86 @@
+package zota
+
+import (
+	"errors"
+	"fmt"
+)
+
+// OrderStatusCode represents the status of a Zota order
+type OrderStatusCode string
+
+const (
+	StatusCreated    OrderStatusCode = "CREATED"
+	StatusPending    OrderStatusCode = "PENDING"
+	StatusProcessing OrderStatusCode = "PROCESSING"
+	StatusUnknown    OrderStatusCode = "UNKNOWN"
+	StatusApproved   OrderStatusCode = "APPROVED"
+	StatusDeclined   OrderStatusCode = "DECLINED"
+	StatusFiltered   OrderStatusCode = "FILTERED"
+	StatusError      OrderStatusCode = "ERROR"
+)
+
+// ErrIllegalTransition is returned by Transition
+// when an order can not change from one status to another
+var ErrIllegalTransition = errors.New("illegal status transition")
+
+// statusTransitions contains the statuses an order can move to from every in flight status
+// final statuses can not change
+var statusTransitions = map[OrderStatusCode][]OrderStatusCode{
+	StatusCreated:    {StatusPending, StatusProcessing, StatusUnknown, StatusApproved, StatusDeclined, StatusFiltered, StatusError},
+	StatusPending:    {StatusProcessing, StatusUnknown, StatusApproved, StatusDeclined, StatusFiltered, StatusError},
+	StatusProcessing: {StatusPending, StatusUnknown, StatusApproved, StatusDeclined, StatusFiltered, StatusError},
+	StatusUnknown:    {StatusPending, StatusProcessing, StatusApproved, StatusDeclined, StatusFiltered, StatusError},
+	StatusApproved:   {},
+	StatusDeclined:   {},
+	StatusFiltered:   {},
+	StatusError:      {},
+}
+
+// IsValid reports whether the status is a known Zota order status
+func (s OrderStatusCode) IsValid() bool {
+	_, ok := statusTransitions[s]
+	return ok
+}
+
+// IsFinal reports whether the status can not change anymore
+// APPROVED, DECLINED, FILTERED and ERROR are final
+func (s OrderStatusCode) IsFinal() bool {
+	next, ok := statusTransitions[s]
+	return ok && len(next) == 0
+}
+
+// IsSuccess reports whether the order has been completed successfully
+func (s OrderStatusCode) IsSuccess() bool {
+	return s == StatusApproved
+}
+
+// CanTransition reports whether an order can change from s to next
+// a status can always be repeated, e.g. by a duplicate callback
+func (s OrderStatusCode) CanTransition(next OrderStatusCode) bool {
+	if !s.IsValid() || !next.IsValid() {
+		return false
+	}
+	if s == next {
+		return true
+	}
+	for _, n := range statusTransitions[s] {
+		if n == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Transition checks that an order can change from one status to another
+// an empty from is the status of an order without a known status yet
+// returns an error wrapping ErrIllegalTransition if the change is not allowed,
+// e.g. PROCESSING arriving after APPROVED
+func Transition(from OrderStatusCode, to OrderStatusCode) error {
+	if from == "" && to.IsValid() {
+		return nil
+	}
+	if !from.CanTransition(to) {
+		return fmt.Errorf("%w from %v to %v", ErrIllegalTransition, from, to)
+	}
+	return nil
+}
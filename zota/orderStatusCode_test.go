@@ -0,0 +1,62 @@
+package zota
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrderStatusCode(t *testing.T) {
+	for _, s := range []OrderStatusCode{StatusApproved, StatusDeclined, StatusFiltered, StatusError} {
+		assert.True(t, s.IsFinal(), s)
+		assert.True(t, s.IsValid(), s)
+	}
+	for _, s := range []OrderStatusCode{StatusCreated, StatusPending, StatusProcessing, StatusUnknown} {
+		assert.False(t, s.IsFinal(), s)
+		assert.True(t, s.IsValid(), s)
+		assert.False(t, s.IsSuccess(), s)
+	}
+	assert.True(t, StatusApproved.IsSuccess())
+	assert.False(t, StatusDeclined.IsSuccess())
+	assert.False(t, OrderStatusCode("REFUNDED").IsValid())
+	assert.False(t, OrderStatusCode("REFUNDED").IsFinal())
+}
+
+func TestTransition(t *testing.T) {
+	tests := []struct {
+		from, to OrderStatusCode
+		expected error
+	}{
+		{"", StatusCreated, nil},
+		{"", StatusApproved, nil},
+		{StatusCreated, StatusPending, nil},
+		{StatusPending, StatusProcessing, nil},
+		{StatusProcessing, StatusApproved, nil},
+		{StatusUnknown, StatusDeclined, nil},
+		{StatusProcessing, StatusProcessing, nil},
+		{StatusApproved, StatusApproved, nil},
+		{StatusApproved, StatusProcessing, fmt.Errorf("%w from APPROVED to PROCESSING", ErrIllegalTransition)},
+		{StatusDeclined, StatusApproved, fmt.Errorf("%w from DECLINED to APPROVED", ErrIllegalTransition)},
+		{StatusPending, StatusCreated, fmt.Errorf("%w from PENDING to CREATED", ErrIllegalTransition)},
+		{StatusPending, "REFUNDED", fmt.Errorf("%w from PENDING to REFUNDED", ErrIllegalTransition)},
+		{"", "REFUNDED", fmt.Errorf("%w from  to REFUNDED", ErrIllegalTransition)},
+	}
+	for _, test := range tests {
+		err := Transition(test.from, test.to)
+		assert.Equal(t, test.expected, err, "%v -> %v", test.from, test.to)
+		if err != nil {
+			assert.True(t, errors.Is(err, ErrIllegalTransition))
+		}
+	}
+}
+
+func TestOrderStatusCode_JSON(t *testing.T) {
+	var res OrderStatusResult
+	err := json.Unmarshal([]byte(`{"code":"200","data":{"status":"APPROVED"}}`), &res)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, StatusApproved, res.Status)
+	assert.True(t, res.Status.IsFinal())
+}
@@ -1,7 +1,7 @@
 package zota
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"net/http"
 	"reflect"
@@ -31,6 +31,9 @@ type OrdersReportResult struct {
 	Code         string `json:"code"`
 	OrdersReport string `json:"data"`
 	Message      string `json:"message"`
+
+	//DryRun is set if the request was not sent, see DryRunMode
+	DryRun bool `json:"-"`
 }
 
 var mockedOrdersReportResult *OrdersReportResult
@@ -39,6 +42,39 @@ var mockedOrdersReportResult *OrdersReportResult
 // generate sign and
 // init a orders report request to Zota API
 func (s *SDK) OrdersReport(d OrdersReport) (res OrdersReportResult, err error) {
+	return s.OrdersReportContext(context.Background(), d)
+}
+
+// OrdersReportContext is OrdersReport with a context
+// the request is cancelled when ctx is done
+func (s *SDK) OrdersReportContext(ctx context.Context, d OrdersReport) (res OrdersReportResult, err error) {
+
+	//validate and sign the OrdersReport
+	r, err := s.PrepareOrdersReport(d)
+	if err != nil {
+		return
+	}
+
+	//if mockedOrdersReportResult is set return it as response
+	//only for testing
+	if mockedOrdersReportResult != nil {
+		res = *mockedOrdersReportResult
+		mockedOrdersReportResult = nil
+		return
+	}
+
+	resp, err := s.Send(ctx, r)
+	if err != nil {
+		return
+	}
+	return resp.OrdersReportResult()
+}
+
+// PrepareOrdersReport init validation of the SDK struct and the OrdersReport
+// generate sign and
+// returns the orders report request to be sent later with Send
+// the request is signed with the current timestamp
+func (s *SDK) PrepareOrdersReport(d OrdersReport) (r PreparedRequest, err error) {
 
 	//validate that SDK is properly initialized
 	err = s.validate()
@@ -61,14 +97,6 @@ func (s *SDK) OrdersReport(d OrdersReport) (res OrdersReportResult, err error) {
 	//generate new UUID
 	d.RequestID = uuid.New().String()
 
-	//if mockedOrdersReportResult is set return it as response
-	//only for testing
-	if mockedOrdersReportResult != nil {
-		res = *mockedOrdersReportResult
-		mockedOrdersReportResult = nil
-		return
-	}
-
 	//generate signature
 	d.Signature = s.sign(d.MerchantID, d.DateType, d.EndpointIds, d.FromDate, d.RequestID, d.Statuses, d.Timestamp, d.ToDate, d.Types)
 
@@ -77,23 +105,7 @@ func (s *SDK) OrdersReport(d OrdersReport) (res OrdersReportResult, err error) {
 		return
 	}
 
-	code, body, err := s.httpDo(http.MethodGet, fmt.Sprintf("%v/api/v1/query/orders-report/csv/?%v", s.ApiBaseURL, v.Encode()), []byte(""))
-	if err != nil {
-		return
-	}
-
-	if code != 200 {
-		err = json.Unmarshal(body, &res)
-		if err != nil {
-			err = fmt.Errorf("json Unmarshal err:%v", err)
-			return
-		}
-		return
-	}
-	res = OrdersReportResult{
-		Code:         "200",
-		OrdersReport: string(body),
-	}
+	r = newPreparedRequest(OperationOrdersReport, "", "", http.MethodGet, fmt.Sprintf("%v/api/v1/query/orders-report/csv/?%v", s.ApiBaseURL, v.Encode()), nil, d.Signature)
 	return
 }
 
@@ -116,5 +128,5 @@ func (d *OrdersReport) validate() error {
 			return fmt.Errorf("%v is required", fieldName)
 		}
 	}
-	return nil
+	return validateStatuses(d.Statuses)
 }
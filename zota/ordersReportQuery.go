@@ -0,0 +1,120 @@
+package zota
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ReportDateType represents the date the orders of an orders report are selected by
+type ReportDateType string
+
+const (
+	// ReportDateCreated selects the orders by creation date
+	ReportDateCreated ReportDateType = "created"
+	// ReportDateEnded selects the orders by the date they reached a final status
+	ReportDateEnded ReportDateType = "ended"
+)
+
+// OrderType represents the type of a Zota order
+type OrderType string
+
+const (
+	OrderTypeSale   OrderType = "SALE"
+	OrderTypePayout OrderType = "PAYOUT"
+)
+
+// MaxReportRange is the longest range of an OrdersReportQuery
+// longer ranges are fetched with OrdersReportRange
+const MaxReportRange = 31 * 24 * time.Hour
+
+// OrdersReportQuery builds the filters of an OrdersReport
+// the orders are selected in the range [From, To)
+// ranges of whole days are sent as dates, other ranges as date times in UTC
+type OrdersReportQuery struct {
+	DateType    ReportDateType
+	From        time.Time
+	To          time.Time
+	Statuses    []OrderStatusCode
+	Types       []OrderType
+	EndpointIDs []string
+}
+
+// Build validates the query and returns the OrdersReport to be sent
+func (q OrdersReportQuery) Build() (OrdersReport, error) {
+	err := q.validate()
+	if err != nil {
+		return OrdersReport{}, err
+	}
+
+	d := OrdersReport{DateType: string(q.DateType), EndpointIds: strings.Join(q.EndpointIDs, ",")}
+	d.FromDate, d.ToDate = ReportWindow{From: q.From.UTC(), To: q.To.UTC()}.dates()
+
+	statuses := make([]string, len(q.Statuses))
+	for i, status := range q.Statuses {
+		statuses[i] = string(status)
+	}
+	d.Statuses = strings.Join(statuses, ",")
+
+	types := make([]string, len(q.Types))
+	for i, t := range q.Types {
+		types[i] = string(t)
+	}
+	d.Types = strings.Join(types, ",")
+	return d, nil
+}
+
+// validate the instance of OrdersReportQuery
+// if not valid returns an error
+func (q OrdersReportQuery) validate() error {
+	switch q.DateType {
+	case ReportDateCreated, ReportDateEnded:
+	case "":
+		return fmt.Errorf("DateType is required")
+	default:
+		return fmt.Errorf("unknown DateType %q", q.DateType)
+	}
+
+	if q.From.IsZero() {
+		return fmt.Errorf("From is required")
+	}
+	if q.To.IsZero() {
+		return fmt.Errorf("To is required")
+	}
+	if !q.From.Before(q.To) {
+		return fmt.Errorf("From must be before To")
+	}
+	if q.To.Sub(q.From) > MaxReportRange {
+		return fmt.Errorf("range longer than %v days", int(MaxReportRange/(24*time.Hour)))
+	}
+
+	for _, status := range q.Statuses {
+		if !status.IsValid() {
+			return fmt.Errorf("unknown status %q", status)
+		}
+	}
+	for _, t := range q.Types {
+		if t != OrderTypeSale && t != OrderTypePayout {
+			return fmt.Errorf("unknown order type %q", t)
+		}
+	}
+	for _, id := range q.EndpointIDs {
+		if id == "" || strings.Contains(id, ",") {
+			return fmt.Errorf("invalid endpoint id %q", id)
+		}
+	}
+	return nil
+}
+
+// validateStatuses checks the comma separated statuses of an OrdersReport
+func validateStatuses(statuses string) error {
+	if statuses == "" {
+		return nil
+	}
+	for _, status := range strings.Split(statuses, ",") {
+		if !OrderStatusCode(strings.TrimSpace(status)).IsValid() {
+			return fmt.Errorf("unknown status %q", status)
+		}
+	}
+	return nil
+}
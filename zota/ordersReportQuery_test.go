@@ -0,0 +1,78 @@
+package zota
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOrdersReportQuery_Build(t *testing.T) {
+	q := OrdersReportQuery{
+		DateType:    ReportDateCreated,
+		From:        time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC),
+		Statuses:    []OrderStatusCode{StatusApproved, StatusDeclined},
+		Types:       []OrderType{OrderTypeSale, OrderTypePayout},
+		EndpointIDs: []string{"503368", "503365"},
+	}
+	d, err := q.Build()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, OrdersReport{
+		DateType:    "created",
+		EndpointIds: "503368,503365",
+		FromDate:    "2020-08-01",
+		ToDate:      "2020-08-31",
+		Statuses:    "APPROVED,DECLINED",
+		Types:       "SALE,PAYOUT",
+	}, d)
+
+	//times are sent in UTC
+	tz := time.FixedZone("UTC+2", 2*60*60)
+	d, err = OrdersReportQuery{
+		DateType: ReportDateEnded,
+		From:     time.Date(2020, 8, 1, 12, 0, 0, 0, tz),
+		To:       time.Date(2020, 8, 1, 14, 30, 0, 0, tz),
+	}.Build()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, OrdersReport{DateType: "ended", FromDate: "2020-08-01 10:00:00", ToDate: "2020-08-01 12:30:00"}, d)
+}
+
+func TestOrdersReportQuery_Validate(t *testing.T) {
+	from := time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC)
+	valid := OrdersReportQuery{DateType: ReportDateCreated, From: from, To: from.AddDate(0, 0, 1)}
+
+	tests := []struct {
+		name          string
+		mutate        func(q *OrdersReportQuery)
+		expectedError error
+	}{
+		{"valid", func(q *OrdersReportQuery) {}, nil},
+		{"missing date type", func(q *OrdersReportQuery) { q.DateType = "" }, fmt.Errorf("DateType is required")},
+		{"unknown date type", func(q *OrdersReportQuery) { q.DateType = "updated" }, fmt.Errorf("unknown DateType %q", "updated")},
+		{"missing from", func(q *OrdersReportQuery) { q.From = time.Time{} }, fmt.Errorf("From is required")},
+		{"missing to", func(q *OrdersReportQuery) { q.To = time.Time{} }, fmt.Errorf("To is required")},
+		{"to before from", func(q *OrdersReportQuery) { q.To = from.Add(-time.Hour) }, fmt.Errorf("From must be before To")},
+		{"empty range", func(q *OrdersReportQuery) { q.To = from }, fmt.Errorf("From must be before To")},
+		{"max range", func(q *OrdersReportQuery) { q.To = from.Add(MaxReportRange) }, nil},
+		{"range too long", func(q *OrdersReportQuery) { q.To = from.Add(MaxReportRange + time.Hour) }, fmt.Errorf("range longer than 31 days")},
+		{"unknown status", func(q *OrdersReportQuery) { q.Statuses = []OrderStatusCode{StatusApproved, "approved"} }, fmt.Errorf("unknown status %q", "approved")},
+		{"unknown type", func(q *OrdersReportQuery) { q.Types = []OrderType{"DEPOSIT"} }, fmt.Errorf("unknown order type %q", "DEPOSIT")},
+		{"empty endpoint", func(q *OrdersReportQuery) { q.EndpointIDs = []string{""} }, fmt.Errorf("invalid endpoint id %q", "")},
+		{"endpoint list", func(q *OrdersReportQuery) { q.EndpointIDs = []string{"1,2"} }, fmt.Errorf("invalid endpoint id %q", "1,2")},
+	}
+
+	for _, test := range tests {
+		q := valid
+		test.mutate(&q)
+		_, err := q.Build()
+		assert.Equal(t, test.expectedError, err, test.name)
+	}
+}
+
+func TestSDK_PrepareOrdersReportUnknownStatus(t *testing.T) {
+	//unknown statuses are rejected before signing
+	_, err := testPollSDK(nil).PrepareOrdersReport(OrdersReport{DateType: "created", FromDate: "2020-08-01", ToDate: "2020-08-02", Statuses: "APPROVED,OK"})
+	assert.Equal(t, fmt.Errorf("unknown status %q", "OK"), err)
+}
@@ -0,0 +1,347 @@
+package zota
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ReportDateLayout and ReportDateTimeLayout are the layouts of OrdersReport FromDate and ToDate
+const (
+	ReportDateLayout     = "2006-01-02"
+	ReportDateTimeLayout = "2006-01-02 15:04:05"
+)
+
+// ReportWindow represents the time range [From, To) of an orders report request
+type ReportWindow struct {
+	From time.Time
+	To   time.Time
+}
+
+// String returns the window as "from/to"
+func (w ReportWindow) String() string {
+	return w.From.Format(ReportDateTimeLayout) + "/" + w.To.Format(ReportDateTimeLayout)
+}
+
+// dates returns the FromDate and ToDate of the window
+// windows of whole days use dates with an inclusive ToDate,
+// other windows use date times and overlap at the boundaries
+func (w ReportWindow) dates() (string, string) {
+	if isMidnight(w.From) && isMidnight(w.To) {
+		return w.From.Format(ReportDateLayout), w.To.AddDate(0, 0, -1).Format(ReportDateLayout)
+	}
+	return w.From.Format(ReportDateTimeLayout), w.To.Format(ReportDateTimeLayout)
+}
+
+// split returns the two halves of the window, rounded to the minute
+// returns false if the rounded middle is not inside the window
+func (w ReportWindow) split() (ReportWindow, ReportWindow, bool) {
+	mid := w.From.Add(w.To.Sub(w.From) / 2).Truncate(time.Minute)
+	if !w.From.Before(mid) || !mid.Before(w.To) {
+		return w, ReportWindow{}, false
+	}
+	return ReportWindow{From: w.From, To: mid}, ReportWindow{From: mid, To: w.To}, true
+}
+
+// ReportWindowError is the failure of the window of an OrdersReportRange
+type ReportWindowError struct {
+	Window ReportWindow
+	Err    error
+}
+
+// Error implements error
+func (e ReportWindowError) Error() string {
+	return fmt.Sprintf("window %v: %v", e.Window, e.Err)
+}
+
+// Unwrap returns the error of the window
+func (e ReportWindowError) Unwrap() error {
+	return e.Err
+}
+
+// ReportRangeError lists the failed windows of an OrdersReportRange
+// the windows can be fetched again with ReportRangeOptions.Windows
+type ReportRangeError []ReportWindowError
+
+// Error implements error
+func (e ReportRangeError) Error() string {
+	msgs := make([]string, len(e))
+	for i, w := range e {
+		msgs[i] = w.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// Windows returns the failed windows
+func (e ReportRangeError) Windows() []ReportWindow {
+	windows := make([]ReportWindow, len(e))
+	for i, w := range e {
+		windows[i] = w.Window
+	}
+	return windows
+}
+
+// ReportRangeOptions configures OrdersReportRange
+// zero values are replaced by the defaults
+type ReportRangeOptions struct {
+	// Window is the size of the windows, 24 hours by default
+	Window time.Duration
+	// MinWindow is the smallest window when a window is split, 1 hour by default, at least 1 minute
+	MinWindow time.Duration
+	// MaxRows splits a window returning more rows, not limited by default
+	// a window which can not be split returns all its rows
+	MaxRows int
+	// Concurrency is the number of windows fetched at once, 2 by default
+	Concurrency int
+	// Windows are fetched instead of splitting the range, e.g. the failed windows of a previous run
+	Windows []ReportWindow
+}
+
+// withDefaults returns the options with the zero values replaced by the defaults
+func (o ReportRangeOptions) withDefaults() ReportRangeOptions {
+	if o.Window <= 0 {
+		o.Window = 24 * time.Hour
+	}
+	if o.MinWindow <= 0 {
+		o.MinWindow = time.Hour
+	}
+	//windows are split at whole minutes
+	if o.MinWindow < time.Minute {
+		o.MinWindow = time.Minute
+	}
+	if o.Concurrency <= 0 {
+		o.Concurrency = 2
+	}
+	return o
+}
+
+// ReportRange is the merged result of the windows of an OrdersReportRange
+// the rows are ordered by window, duplicate order IDs are dropped
+//
+//	for r.Next() {
+//		row := r.Row()
+//	}
+//	err := r.Err()
+type ReportRange struct {
+	rows   chan ReportRow
+	cancel context.CancelFunc
+	row    ReportRow
+
+	mu     sync.Mutex
+	failed ReportRangeError
+}
+
+// OrdersReportRange fetches the orders report of the FromDate to ToDate range of d
+// split into windows fetched with bounded concurrency
+// FromDate and ToDate are dates, ToDate inclusive, or date times, ToDate exclusive
+// a window timing out or returning more than MaxRows rows is split in two, down to MinWindow
+// a window at MinWindow is not limited to MaxRows
+// the failed windows are reported by Err and do not stop the other windows
+func (s *SDK) OrdersReportRange(ctx context.Context, d OrdersReport, opts ReportRangeOptions) (*ReportRange, error) {
+	err := d.validate()
+	if err != nil {
+		return nil, err
+	}
+	opts = opts.withDefaults()
+
+	windows := opts.Windows
+	if len(windows) == 0 {
+		from, _, err := parseReportDate(d.FromDate)
+		if err != nil {
+			return nil, fmt.Errorf("FromDate %v", err)
+		}
+		to, dateOnly, err := parseReportDate(d.ToDate)
+		if err != nil {
+			return nil, fmt.Errorf("ToDate %v", err)
+		}
+		if dateOnly {
+			to = to.AddDate(0, 0, 1)
+		}
+		if !from.Before(to) {
+			return nil, fmt.Errorf("FromDate must be before ToDate")
+		}
+		windows = splitReportRange(from, to, opts.Window)
+	}
+
+	err = s.initHttpClient()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(withDefaultPriority(ctx, PriorityBatch))
+	r := &ReportRange{rows: make(chan ReportRow, 100), cancel: cancel}
+
+	//a window is a slot of the semaphore until it is emitted,
+	//so at most Concurrency windows are held in memory
+	slots := make(chan struct{}, opts.Concurrency)
+	results := make([]chan windowRows, len(windows))
+	for i := range results {
+		results[i] = make(chan windowRows, 1)
+	}
+
+	go func() {
+		for i, w := range windows {
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				results[i] <- windowRows{errs: ReportRangeError{{Window: w, Err: ctx.Err()}}}
+				continue
+			}
+			go func(i int, w ReportWindow) {
+				rows, errs := s.fetchReportWindow(ctx, d, w, opts)
+				results[i] <- windowRows{rows: rows, errs: errs, slot: true}
+			}(i, w)
+		}
+	}()
+
+	go func() {
+		defer close(r.rows)
+		var previous map[int64]bool
+		for _, result := range results {
+			res := <-result
+			current := make(map[int64]bool, len(res.rows))
+			for _, row := range res.rows {
+				if previous[row.ID] || current[row.ID] {
+					continue
+				}
+				current[row.ID] = true
+				select {
+				case r.rows <- row:
+				case <-ctx.Done():
+				}
+			}
+			previous = current
+			if len(res.errs) > 0 {
+				r.mu.Lock()
+				r.failed = append(r.failed, res.errs...)
+				r.mu.Unlock()
+			}
+			if res.slot {
+				<-slots
+			}
+		}
+	}()
+
+	return r, nil
+}
+
+// windowRows is the result of the fetch of a window
+type windowRows struct {
+	rows []ReportRow
+	errs ReportRangeError
+	slot bool
+}
+
+// fetchReportWindow fetches the rows of a window
+// splitting the window when it is too big
+// an empty window has no rows and is not fetched
+func (s *SDK) fetchReportWindow(ctx context.Context, d OrdersReport, w ReportWindow, opts ReportRangeOptions) ([]ReportRow, ReportRangeError) {
+	if !w.From.Before(w.To) {
+		return nil, nil
+	}
+	d.FromDate, d.ToDate = w.dates()
+	first, second, ok := w.split()
+	canSplit := ok && w.To.Sub(w.From) > opts.MinWindow
+
+	//MaxRows only decides to split, a window which can not be split is streamed whole
+	maxRows := 0
+	if canSplit {
+		maxRows = opts.MaxRows
+	}
+	rows, err := s.fetchReportRows(ctx, d, maxRows)
+
+	tooBig := errors.Is(err, errTooManyRows) || (errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil)
+	if tooBig && canSplit {
+		rows, errs := s.fetchReportWindow(ctx, d, first, opts)
+		more, moreErrs := s.fetchReportWindow(ctx, d, second, opts)
+		return append(rows, more...), append(errs, moreErrs...)
+	}
+	if err != nil {
+		return nil, ReportRangeError{{Window: w, Err: err}}
+	}
+	return rows, nil
+}
+
+// errTooManyRows is returned by fetchReportRows when the report has more than maxRows rows
+var errTooManyRows = errors.New("too many rows")
+
+// fetchReportRows downloads the rows of an orders report
+func (s *SDK) fetchReportRows(ctx context.Context, d OrdersReport, maxRows int) ([]ReportRow, error) {
+	stream, err := s.OrdersReportStream(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	defer stream.Close()
+
+	var rows []ReportRow
+	for stream.Next() {
+		if maxRows > 0 && len(rows) >= maxRows {
+			return nil, errTooManyRows
+		}
+		rows = append(rows, stream.Row())
+	}
+	return rows, stream.Err()
+}
+
+// Next returns the next row
+// returns false once every window has been emitted or after Close
+func (r *ReportRange) Next() bool {
+	row, ok := <-r.rows
+	r.row = row
+	return ok
+}
+
+// Row returns the row of the last call to Next
+func (r *ReportRange) Row() ReportRow {
+	return r.row
+}
+
+// Err returns the ReportRangeError of the failed windows once Next returned false
+func (r *ReportRange) Err() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.failed) == 0 {
+		return nil
+	}
+	return r.failed
+}
+
+// Close stops the fetch of the remaining windows
+func (r *ReportRange) Close() {
+	r.cancel()
+	for range r.rows {
+	}
+}
+
+// splitReportRange splits [from, to) into windows of size
+func splitReportRange(from time.Time, to time.Time, size time.Duration) []ReportWindow {
+	var windows []ReportWindow
+	for start := from; start.Before(to); start = start.Add(size) {
+		end := start.Add(size)
+		if end.After(to) {
+			end = to
+		}
+		windows = append(windows, ReportWindow{From: start, To: end})
+	}
+	return windows
+}
+
+// parseReportDate parses a FromDate or ToDate in UTC
+// returns whether the value is a date without time
+func parseReportDate(s string) (time.Time, bool, error) {
+	if t, err := time.Parse(ReportDateLayout, s); err == nil {
+		return t, true, nil
+	}
+	if t, err := time.Parse(ReportDateTimeLayout, s); err == nil {
+		return t, false, nil
+	}
+	return time.Time{}, false, fmt.Errorf("has invalid format")
+}
+
+// isMidnight reports whether t is at 00:00:00
+func isMidnight(t time.Time) bool {
+	return t.Equal(t.Truncate(24 * time.Hour))
+}
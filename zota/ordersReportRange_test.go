@@ -0,0 +1,263 @@
+package zota
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ClientMockReportRange serves the orders created in the fromDate to toDate range of the request
+// both dates are inclusive, a date covers the whole day
+// an order is last updated at its creation unless updated has the order ID
+// implement httpClient interface
+type ClientMockReportRange struct {
+	created []time.Time
+	updated map[int64]time.Time
+	status  map[int64]OrderStatusCode
+	fail    map[string]bool
+	delay   time.Duration
+
+	mu       sync.Mutex
+	calls    []string
+	inFlight int
+	maxAtOne int
+}
+
+func (c *ClientMockReportRange) Do(req *http.Request) (*http.Response, error) {
+	q := req.URL.Query()
+	from, to := q.Get("fromDate"), q.Get("toDate")
+
+	c.mu.Lock()
+	c.calls = append(c.calls, from+"/"+to)
+	c.inFlight++
+	if c.inFlight > c.maxAtOne {
+		c.maxAtOne = c.inFlight
+	}
+	c.mu.Unlock()
+	time.Sleep(c.delay)
+	defer func() {
+		c.mu.Lock()
+		c.inFlight--
+		c.mu.Unlock()
+	}()
+
+	if c.fail[from] {
+		body := `{"code":"400","message":"bad request"}`
+		return &http.Response{StatusCode: 400, Body: io.NopCloser(strings.NewReader(body))}, nil
+	}
+
+	start, _, _ := parseReportDate(from)
+	end, dateOnly, _ := parseReportDate(to)
+	if dateOnly {
+		end = end.AddDate(0, 0, 1).Add(-time.Second)
+	}
+	var buf bytes.Buffer
+	buf.WriteString("id,merchant_order_id,status,created_at,last_update_at\n")
+	for i, t := range c.created {
+		id := int64(i + 1)
+		updated, ok := c.updated[id]
+		if !ok {
+			updated = t
+		}
+		if !t.Before(start) && !t.After(end) {
+			fmt.Fprintf(&buf, "%d,m-%d,%v,%v,%v\n", id, id, c.status[id], t.Format(ReportTimeLayout), updated.Format(ReportTimeLayout))
+		}
+	}
+	return &http.Response{StatusCode: 200, Body: io.NopCloser(&buf)}, nil
+}
+
+func (c *ClientMockReportRange) Calls() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.calls...)
+}
+
+// reportTimes returns the times at the offsets from 2020-08-01
+func reportTimes(offsets ...time.Duration) []time.Time {
+	start := time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC)
+	times := make([]time.Time, len(offsets))
+	for i, o := range offsets {
+		times[i] = start.Add(o)
+	}
+	return times
+}
+
+// rangeIDs collects the ids of the rows of a ReportRange
+func rangeIDs(r *ReportRange) []int64 {
+	var ids []int64
+	for r.Next() {
+		ids = append(ids, r.Row().ID)
+	}
+	return ids
+}
+
+func TestSDK_OrdersReportRange(t *testing.T) {
+	client := &ClientMockReportRange{created: reportTimes(0, 6*time.Hour, 30*time.Hour, 47*time.Hour, 50*time.Hour)}
+	d := OrdersReport{DateType: "created", FromDate: "2020-08-01", ToDate: "2020-08-03"}
+
+	r, err := testPollSDK(client).OrdersReportRange(context.Background(), d, ReportRangeOptions{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, rangeIDs(r))
+	assert.Equal(t, nil, r.Err())
+	assert.ElementsMatch(t, []string{"2020-08-01/2020-08-01", "2020-08-02/2020-08-02", "2020-08-03/2020-08-03"}, client.Calls())
+}
+
+func TestSDK_OrdersReportRangeHourWindows(t *testing.T) {
+	//orders at the boundaries are returned by both windows
+	client := &ClientMockReportRange{created: reportTimes(0, time.Hour, 90*time.Minute, 2*time.Hour, 3*time.Hour)}
+	d := OrdersReport{DateType: "created", FromDate: "2020-08-01 00:00:00", ToDate: "2020-08-01 03:00:00"}
+
+	r, err := testPollSDK(client).OrdersReportRange(context.Background(), d, ReportRangeOptions{Window: time.Hour})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, rangeIDs(r))
+	assert.Equal(t, nil, r.Err())
+	assert.ElementsMatch(t, []string{
+		"2020-08-01 00:00:00/2020-08-01 01:00:00",
+		"2020-08-01 01:00:00/2020-08-01 02:00:00",
+		"2020-08-01 02:00:00/2020-08-01 03:00:00",
+	}, client.Calls())
+}
+
+func TestSDK_OrdersReportRangeAdaptive(t *testing.T) {
+	client := &ClientMockReportRange{created: reportTimes(30*time.Minute, 390*time.Minute, 750*time.Minute, 1110*time.Minute, 36*time.Hour)}
+	d := OrdersReport{DateType: "created", FromDate: "2020-08-01", ToDate: "2020-08-02"}
+
+	r, err := testPollSDK(client).OrdersReportRange(context.Background(), d, ReportRangeOptions{MaxRows: 2, Concurrency: 1})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []int64{1, 2, 3, 4, 5}, rangeIDs(r))
+	assert.Equal(t, nil, r.Err())
+	assert.Equal(t, []string{
+		"2020-08-01/2020-08-01",
+		"2020-08-01 00:00:00/2020-08-01 12:00:00",
+		"2020-08-01 12:00:00/2020-08-02 00:00:00",
+		"2020-08-02/2020-08-02",
+	}, client.Calls())
+
+	//a window is not split below MinWindow and returns more than MaxRows rows
+	client = &ClientMockReportRange{created: reportTimes(0, time.Minute, 2*time.Minute)}
+	r, err = testPollSDK(client).OrdersReportRange(context.Background(), d, ReportRangeOptions{MaxRows: 2, MinWindow: 12 * time.Hour, Concurrency: 1})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []int64{1, 2, 3}, rangeIDs(r))
+	assert.Equal(t, nil, r.Err())
+}
+
+func TestSDK_OrdersReportRangeMinuteWindow(t *testing.T) {
+	//a minute window can not be split, a MinWindow under a minute does not split it forever
+	client := &ClientMockReportRange{created: reportTimes(0, 10*time.Second, 20*time.Second)}
+	d := OrdersReport{DateType: "created", FromDate: "2020-08-01 00:00:00", ToDate: "2020-08-01 00:01:00"}
+
+	r, err := testPollSDK(client).OrdersReportRange(context.Background(), d, ReportRangeOptions{MaxRows: 2, MinWindow: 30 * time.Second})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []int64{1, 2, 3}, rangeIDs(r))
+	assert.Equal(t, nil, r.Err())
+	assert.Equal(t, []string{"2020-08-01 00:00:00/2020-08-01 00:01:00"}, client.Calls())
+
+	//an empty window is not fetched
+	midnight := time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC)
+	r, err = testPollSDK(client).OrdersReportRange(context.Background(), d, ReportRangeOptions{Windows: []ReportWindow{{From: midnight, To: midnight}}})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []int64(nil), rangeIDs(r))
+	assert.Equal(t, nil, r.Err())
+	assert.Equal(t, 1, len(client.Calls()))
+}
+
+func TestReportWindow_Split(t *testing.T) {
+	from := time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC)
+	first, second, ok := ReportWindow{From: from, To: from.Add(2 * time.Hour)}.split()
+	assert.True(t, ok)
+	assert.Equal(t, from.Add(time.Hour), first.To)
+	assert.Equal(t, from.Add(time.Hour), second.From)
+
+	_, _, ok = ReportWindow{From: from, To: from.Add(time.Minute)}.split()
+	assert.False(t, ok)
+}
+
+func TestSDK_OrdersReportRangePartialFailure(t *testing.T) {
+	client := &ClientMockReportRange{
+		created: reportTimes(0, 30*time.Hour, 50*time.Hour),
+		fail:    map[string]bool{"2020-08-02": true},
+	}
+	s := testPollSDK(client)
+	d := OrdersReport{DateType: "created", FromDate: "2020-08-01", ToDate: "2020-08-03"}
+
+	r, err := s.OrdersReportRange(context.Background(), d, ReportRangeOptions{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []int64{1, 3}, rangeIDs(r))
+
+	rangeErr, ok := r.Err().(ReportRangeError)
+	assert.True(t, ok)
+	day := ReportWindow{From: time.Date(2020, 8, 2, 0, 0, 0, 0, time.UTC), To: time.Date(2020, 8, 3, 0, 0, 0, 0, time.UTC)}
+	assert.Equal(t, []ReportWindow{day}, rangeErr.Windows())
+	assert.Equal(t, "window 2020-08-02 00:00:00/2020-08-03 00:00:00: orders report error code:400 message:bad request", rangeErr.Error())
+
+	//only the failed window is fetched again
+	client.fail = nil
+	r, err = s.OrdersReportRange(context.Background(), d, ReportRangeOptions{Windows: rangeErr.Windows()})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []int64{2}, rangeIDs(r))
+	assert.Equal(t, nil, r.Err())
+	assert.Equal(t, "2020-08-02/2020-08-02", client.Calls()[3])
+}
+
+func TestSDK_OrdersReportRangeConcurrency(t *testing.T) {
+	var created []time.Duration
+	for i := 0; i < 10; i++ {
+		created = append(created, time.Duration(i)*24*time.Hour)
+	}
+	client := &ClientMockReportRange{created: reportTimes(created...), delay: 10 * time.Millisecond}
+	d := OrdersReport{DateType: "created", FromDate: "2020-08-01", ToDate: "2020-08-10"}
+
+	r, err := testPollSDK(client).OrdersReportRange(context.Background(), d, ReportRangeOptions{Concurrency: 3})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, rangeIDs(r))
+	assert.Equal(t, 10, len(client.Calls()))
+	assert.True(t, client.maxAtOne > 1)
+	assert.True(t, client.maxAtOne <= 3)
+}
+
+func TestSDK_OrdersReportRangeClose(t *testing.T) {
+	client := &ClientMockReportRange{created: reportTimes(0, 24*time.Hour, 48*time.Hour), delay: 10 * time.Millisecond}
+	d := OrdersReport{DateType: "created", FromDate: "2020-08-01", ToDate: "2020-08-10"}
+
+	r, err := testPollSDK(client).OrdersReportRange(context.Background(), d, ReportRangeOptions{Concurrency: 1})
+	assert.Equal(t, nil, err)
+	assert.True(t, r.Next())
+	r.Close()
+	assert.False(t, r.Next())
+	assert.True(t, len(client.Calls()) < 10)
+}
+
+func TestSDK_OrdersReportRangeValidation(t *testing.T) {
+	s := testPollSDK(&ClientMockReportRange{})
+	ctx := context.Background()
+
+	_, err := s.OrdersReportRange(ctx, OrdersReport{DateType: "created", FromDate: "2020-08-01"}, ReportRangeOptions{})
+	assert.Equal(t, fmt.Errorf("ToDate is required"), err)
+
+	_, err = s.OrdersReportRange(ctx, OrdersReport{DateType: "created", FromDate: "01/08/2020", ToDate: "2020-08-02"}, ReportRangeOptions{})
+	assert.Equal(t, fmt.Errorf("FromDate has invalid format"), err)
+
+	_, err = s.OrdersReportRange(ctx, OrdersReport{DateType: "created", FromDate: "2020-08-02", ToDate: "2020-08-01"}, ReportRangeOptions{})
+	assert.Equal(t, fmt.Errorf("FromDate must be before ToDate"), err)
+
+	_, err = s.OrdersReportRange(ctx, OrdersReport{DateType: "created", FromDate: "2020-08-01 10:00:00", ToDate: "2020-08-01 10:00:00"}, ReportRangeOptions{})
+	assert.Equal(t, fmt.Errorf("FromDate must be before ToDate"), err)
+}
+
+func TestSDK_OrdersReportRangeTransportError(t *testing.T) {
+	s := testPollSDK(nil)
+	s.Transport = &TransportConfig{RootCAs: []byte("not a certificate")}
+	d := OrdersReport{DateType: "created", FromDate: "2020-08-01", ToDate: "2020-08-02"}
+
+	r, err := s.OrdersReportRange(context.Background(), d, ReportRangeOptions{})
+	assert.Equal(t, (*ReportRange)(nil), r)
+	assert.Equal(t, "RootCAs contains no PEM certificate", err.Error())
+}
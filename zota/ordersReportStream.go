@@ -0,0 +1,157 @@
+package zota
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// ReportStream is an orders report downloaded incrementally
+// it is an io.ReadCloser of the raw csv and an iterator of the rows:
+//
+//	for stream.Next() {
+//		row := stream.Row()
+//	}
+//	err := stream.Err()
+//
+// the csv must be consumed either with Read or WriteTo, or with Next, not both
+// the stream must be closed
+type ReportStream struct {
+	body io.ReadCloser
+	csv  *csv.Reader
+	dec  *reportDecoder
+	row  ReportRow
+	err  error
+	done bool
+}
+
+// NewReportStream creates a ReportStream reading the csv orders report from rc
+// e.g. a report saved with WriteTo
+func NewReportStream(rc io.ReadCloser) *ReportStream {
+	return &ReportStream{body: rc}
+}
+
+// OrdersReportStream init validation of the SDK struct and the OrdersReport
+// generate sign and
+// init a orders report request to Zota API returning the report as a ReportStream
+// the API errors are returned as "orders report error code:%v message:%v"
+func (s *SDK) OrdersReportStream(ctx context.Context, d OrdersReport) (*ReportStream, error) {
+
+	//validate and sign the OrdersReport
+	r, err := s.PrepareOrdersReport(d)
+	if err != nil {
+		return nil, err
+	}
+
+	//if mockedOrdersReportResult is set stream it as response
+	//only for testing
+	if mockedOrdersReportResult != nil {
+		res := *mockedOrdersReportResult
+		mockedOrdersReportResult = nil
+		if res.Code != "200" {
+			return nil, fmt.Errorf("orders report error code:%v message:%v", res.Code, res.Message)
+		}
+		return NewReportStream(ioutil.NopCloser(strings.NewReader(res.OrdersReport))), nil
+	}
+
+	resp, err := s.sendStream(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != 200 {
+		defer resp.Body.Close()
+		var res OrdersReportResult
+		err = json.NewDecoder(resp.Body).Decode(&res)
+		if err != nil {
+			return nil, fmt.Errorf("json Unmarshal err:%v", err)
+		}
+		return nil, fmt.Errorf("orders report error code:%v message:%v", res.Code, res.Message)
+	}
+	return NewReportStream(resp.Body), nil
+}
+
+// Read reads the raw csv, implements io.Reader
+func (r *ReportStream) Read(p []byte) (int, error) {
+	return r.body.Read(p)
+}
+
+// WriteTo writes the raw csv to w, e.g. to save the report to disk
+func (r *ReportStream) WriteTo(w io.Writer) (int64, error) {
+	return io.Copy(w, r.body)
+}
+
+// Close closes the download
+func (r *ReportStream) Close() error {
+	return r.body.Close()
+}
+
+// Next decodes the next row
+// returns false at the end of the report or on error, see Err
+func (r *ReportStream) Next() bool {
+	if r.err != nil || r.done {
+		return false
+	}
+	if r.csv == nil {
+		r.csv = csv.NewReader(r.body)
+		r.csv.FieldsPerRecord = -1
+		r.csv.ReuseRecord = true
+	}
+	if r.dec == nil {
+		header, ok := r.read()
+		if !ok {
+			return false
+		}
+		r.dec = newReportDecoder(append([]string(nil), header...))
+	}
+
+	record, ok := r.read()
+	if !ok {
+		return false
+	}
+	row, err := r.dec.decode(record)
+	if err != nil {
+		line, _ := r.csv.FieldPos(0)
+		r.err = fmt.Errorf("orders report line %v: %v", line, err)
+		return false
+	}
+	r.row = row
+	return true
+}
+
+// read reads the next csv record
+// returns false at the end of the report or on error
+func (r *ReportStream) read() ([]string, bool) {
+	record, err := r.csv.Read()
+	if err == io.EOF {
+		r.done = true
+		return nil, false
+	}
+	if err != nil {
+		r.err = fmt.Errorf("unexpected orders report csv:%v", err)
+		return nil, false
+	}
+	return record, true
+}
+
+// Row returns the row decoded by the last call to Next
+func (r *ReportStream) Row() ReportRow {
+	return r.row
+}
+
+// Err returns the error that stopped Next, nil at the end of the report
+func (r *ReportStream) Err() error {
+	return r.err
+}
+
+// Header returns the csv header, once Next has been called
+func (r *ReportStream) Header() []string {
+	if r.dec == nil {
+		return nil
+	}
+	return r.dec.header
+}
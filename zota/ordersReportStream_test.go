@@ -0,0 +1,137 @@
+package zota
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// generatedReport is a csv orders report generated while it is read
+type generatedReport struct {
+	rows   int
+	next   int
+	buf    bytes.Buffer
+	read   int
+	closed bool
+}
+
+func (g *generatedReport) Read(p []byte) (int, error) {
+	if g.buf.Len() == 0 {
+		if g.next == 0 {
+			g.buf.WriteString("id,status,merchant_order_id,order_amount\n")
+		}
+		if g.next >= g.rows {
+			return 0, io.EOF
+		}
+		g.next++
+		fmt.Fprintf(&g.buf, "%d,APPROVED,m-%d,%d.50\n", g.next, g.next, g.next)
+	}
+	n, err := g.buf.Read(p)
+	g.read += n
+	return n, err
+}
+
+func (g *generatedReport) Close() error {
+	g.closed = true
+	return nil
+}
+
+// ClientMockReportStream streams a generatedReport
+// implement httpClient interface
+type ClientMockReportStream struct {
+	report *generatedReport
+}
+
+func (c *ClientMockReportStream) Do(req *http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: 200, Body: c.report}, nil
+}
+
+var testReportRequest = OrdersReport{DateType: "created", FromDate: "2020-08-01", ToDate: "2020-08-06"}
+
+func TestSDK_OrdersReportStream(t *testing.T) {
+	report := &generatedReport{rows: 100000}
+	sdk := testPollSDK(&ClientMockReportStream{report: report})
+
+	stream, err := sdk.OrdersReportStream(context.Background(), testReportRequest)
+	assert.Equal(t, nil, err)
+
+	assert.True(t, stream.Next())
+	assert.Equal(t, []string{"id", "status", "merchant_order_id", "order_amount"}, stream.Header())
+	assert.Equal(t, ReportRow{ID: 1, Status: StatusApproved, MerchantOrderID: "m-1", OrderAmount: MustParseMoney("1.5")}, stream.Row())
+
+	//the rows are decoded incrementally
+	assert.True(t, report.read < 64*1024)
+
+	count := 1
+	total := MustParseMoney("1.5")
+	for stream.Next() {
+		count++
+		total = total.Add(stream.Row().OrderAmount)
+	}
+	assert.Equal(t, nil, stream.Err())
+	assert.Equal(t, 100000, count)
+	assert.Equal(t, MustParseMoney("5000100000"), total)
+
+	assert.Equal(t, nil, stream.Close())
+	assert.True(t, report.closed)
+}
+
+func TestReportStream_WriteTo(t *testing.T) {
+	report := &generatedReport{rows: 3}
+	stream, err := testPollSDK(&ClientMockReportStream{report: report}).OrdersReportStream(context.Background(), testReportRequest)
+	assert.Equal(t, nil, err)
+	defer stream.Close()
+
+	var buf bytes.Buffer
+	n, err := stream.WriteTo(&buf)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, int64(buf.Len()), n)
+	assert.Equal(t, "id,status,merchant_order_id,order_amount\n1,APPROVED,m-1,1.50\n2,APPROVED,m-2,2.50\n3,APPROVED,m-3,3.50\n", buf.String())
+
+	//the saved report is read back as a stream
+	saved := NewReportStream(io.NopCloser(&buf))
+	var ids []int64
+	for saved.Next() {
+		ids = append(ids, saved.Row().ID)
+	}
+	assert.Equal(t, []int64{1, 2, 3}, ids)
+}
+
+func TestSDK_OrdersReportStreamErrors(t *testing.T) {
+	_, err := testPollSDK(&ClientMockOrdersRepErr{}).OrdersReportStream(context.Background(), testReportRequest)
+	assert.Equal(t, fmt.Errorf("orders report error code:400 message:bad request"), err)
+
+	_, err = testPollSDK(&ClientMockOrdersRepUnexpectedResp{}).OrdersReportStream(context.Background(), testReportRequest)
+	assert.True(t, strings.HasPrefix(err.Error(), "json Unmarshal err:"))
+
+	_, err = testPollSDK(&ClientMockOrdersRepErrDo{}).OrdersReportStream(context.Background(), testReportRequest)
+	assert.Equal(t, fmt.Errorf("do error"), err)
+
+	_, err = testPollSDK(nil).OrdersReportStream(context.Background(), OrdersReport{})
+	assert.NotNil(t, err)
+
+	//a bad row stops the iteration
+	stream := NewReportStream(io.NopCloser(strings.NewReader("id\n1\nx\n2\n")))
+	assert.True(t, stream.Next())
+	assert.False(t, stream.Next())
+	assert.False(t, stream.Next())
+	assert.Equal(t, fmt.Errorf(`orders report line 3: column id: invalid id "x"`), stream.Err())
+}
+
+func TestSDK_OrdersReportStreamMock(t *testing.T) {
+	(&OrdersReportResult{Code: "200", OrdersReport: "id\n7\n"}).SetMockResponse()
+	stream, err := testPollSDK(nil).OrdersReportStream(context.Background(), testReportRequest)
+	assert.Equal(t, nil, err)
+	assert.True(t, stream.Next())
+	assert.Equal(t, int64(7), stream.Row().ID)
+
+	(&OrdersReportResult{Code: "401", Message: "unauthorized"}).SetMockResponse()
+	_, err = testPollSDK(nil).OrdersReportStream(context.Background(), testReportRequest)
+	assert.Equal(t, fmt.Errorf("orders report error code:401 message:unauthorized"), err)
+}
@@ -281,6 +281,15 @@ func Test_OrdersReport_Validate(t *testing.T) {
 			name:          "missing field",
 			mock:          &OrdersReport{},
 			expectedError: fmt.Errorf("DateType is required"),
+		}, {
+			name: "unknown status",
+			mock: &OrdersReport{
+				DateType: "created",
+				FromDate: "2020-08-01",
+				ToDate:   "2020-09-01",
+				Statuses: "APPROVED,REJECTED",
+			},
+			expectedError: fmt.Errorf("unknown status %q", "REJECTED"),
 		},
 	}
 
@@ -1,6 +1,7 @@
 package zota
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -45,6 +46,11 @@ type PayoutResult struct {
 	Code    string           `json:"code"`
 	Data    PayoutResultData `json:"data"`
 	Message string           `json:"message"`
+
+	//EndpointID is the endpoint the order was sent to
+	EndpointID string `json:"-"`
+	//DryRun is set if the order was not sent, see DryRunMode
+	DryRun bool `json:"-"`
 }
 
 type PayoutResultData struct {
@@ -58,6 +64,38 @@ var mockedPayoutResult *PayoutResult
 // generate sign and
 // init a payout request to Zota API
 func (s *SDK) Payout(p PayoutOrder) (res PayoutResult, err error) {
+	return s.PayoutContext(context.Background(), p)
+}
+
+// PayoutContext is Payout with a context
+// the request is cancelled when ctx is done
+func (s *SDK) PayoutContext(ctx context.Context, p PayoutOrder) (res PayoutResult, err error) {
+
+	//validate, route and sign the PayoutOrder
+	r, err := s.PreparePayout(p)
+	if err != nil {
+		return
+	}
+
+	//if mockedPayoutResult is set return it as response
+	//only for testing
+	if mockedPayoutResult != nil {
+		res = *mockedPayoutResult
+		mockedPayoutResult = nil
+		return
+	}
+
+	resp, err := s.Send(ctx, r)
+	if err != nil {
+		return
+	}
+	return resp.PayoutResult()
+}
+
+// PreparePayout init validation of the SDK struct and PayoutOrder
+// generate sign and
+// returns the payout request to be sent later with Send
+func (s *SDK) PreparePayout(p PayoutOrder) (r PreparedRequest, err error) {
 
 	//validate that SDK is properly initialized
 	err = s.validate()
@@ -71,33 +109,39 @@ func (s *SDK) Payout(p PayoutOrder) (res PayoutResult, err error) {
 		return
 	}
 
-	//if mockedPayoutResult is set return it as response
-	//only for testing
-	if mockedPayoutResult != nil {
-		res = *mockedPayoutResult
-		mockedPayoutResult = nil
+	//validate the CustomerBankCode against the bank catalog
+	err = s.bankCatalog().validateBankCode(p.OrderCurrency, p.CustomerBankCode, OperationPayout)
+	if err != nil {
 		return
 	}
 
-	//generate signature
-	p.Signature = s.sign(s.EndpointID, p.MerchantOrderID, p.OrderAmount, p.CustomerEmail, p.CustomerBankAccountNumber)
+	//apply the opt-in PayoutRules
+	err = p.applyRules(s.PayoutRules)
+	if err != nil {
+		return
+	}
 
-	payout, err := json.Marshal(p)
+	//validate the PayoutOrder against its country profile
+	err = s.validatePayoutProfile(p)
 	if err != nil {
 		return
 	}
 
-	_, body, err := s.httpDo(http.MethodPost, fmt.Sprintf("%v/api/v1/payout/request/%v/", s.ApiBaseURL, s.EndpointID), payout)
+	//select the endpoint of the order
+	endpointID, err := s.endpoint(EndpointRoute{Operation: OperationPayout, PaymentMethod: PaymentMethodBank, Currency: p.OrderCurrency, Country: p.CustomerCountryCode})
 	if err != nil {
 		return
 	}
 
-	err = json.Unmarshal(body, &res)
+	//generate signature
+	p.Signature = s.sign(endpointID, p.MerchantOrderID, p.OrderAmount, p.CustomerEmail, p.CustomerBankAccountNumber)
+
+	payout, err := json.Marshal(p)
 	if err != nil {
-		err = fmt.Errorf("json Unmarshal err:%v", err)
 		return
 	}
 
+	r = newPreparedRequest(OperationPayout, endpointID, p.MerchantOrderID, http.MethodPost, fmt.Sprintf("%v/api/v1/payout/request/%v/", s.ApiBaseURL, endpointID), payout, p.Signature)
 	return
 }
 
@@ -0,0 +1,406 @@
+package zota
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// OutboxState represents the state of a payout in the PayoutOutbox
+type OutboxState string
+
+const (
+	// OutboxPending payouts wait to be sent
+	OutboxPending OutboxState = "pending"
+	// OutboxSending payouts are being sent, a payout still sending after a restart is parked
+	OutboxSending OutboxState = "sending"
+	// OutboxSent payouts have been accepted by Zota
+	OutboxSent OutboxState = "sent"
+	// OutboxFailed payouts have been rejected by Zota or could not be sent, e.g. an invalid request
+	OutboxFailed OutboxState = "failed"
+	// OutboxParked payouts have an unknown outcome and must be reconciled
+	OutboxParked OutboxState = "parked"
+	// OutboxCancelled payouts will not be sent
+	OutboxCancelled OutboxState = "cancelled"
+)
+
+// OutboxItem represents a payout of the PayoutOutbox
+// ID is the MerchantOrderID of the payout
+type OutboxItem struct {
+	ID        string          `json:"id"`
+	State     OutboxState     `json:"state"`
+	Request   PreparedRequest `json:"request"`
+	Attempts  int             `json:"attempts"`
+	OrderID   string          `json:"orderID,omitempty"`
+	Code      string          `json:"code,omitempty"`
+	Error     string          `json:"error,omitempty"`
+	CreatedAt time.Time       `json:"createdAt"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+// OutboxStore persists the payouts of a PayoutOutbox
+// Put inserts or replaces the item with the same ID
+type OutboxStore interface {
+	Get(id string) (OutboxItem, bool, error)
+	Put(item OutboxItem) error
+	List() ([]OutboxItem, error)
+}
+
+// MemoryOutboxStore is an OutboxStore keeping the payouts in memory
+type MemoryOutboxStore struct {
+	mu    sync.Mutex
+	items map[string]OutboxItem
+}
+
+// NewMemoryOutboxStore creates an empty MemoryOutboxStore
+func NewMemoryOutboxStore() *MemoryOutboxStore {
+	return &MemoryOutboxStore{items: map[string]OutboxItem{}}
+}
+
+// Get implements OutboxStore
+func (m *MemoryOutboxStore) Get(id string) (OutboxItem, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	item, ok := m.items[id]
+	return item, ok, nil
+}
+
+// Put implements OutboxStore
+func (m *MemoryOutboxStore) Put(item OutboxItem) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.items[item.ID] = item
+	return nil
+}
+
+// List implements OutboxStore
+func (m *MemoryOutboxStore) List() ([]OutboxItem, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	items := make([]OutboxItem, 0, len(m.items))
+	for _, item := range m.items {
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// FileOutboxStore is an OutboxStore keeping the payouts in a json file
+// the file is replaced atomically on every Put
+type FileOutboxStore struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+// Get implements OutboxStore
+func (f *FileOutboxStore) Get(id string) (OutboxItem, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	items, err := f.load()
+	if err != nil {
+		return OutboxItem{}, false, err
+	}
+	item, ok := items[id]
+	return item, ok, nil
+}
+
+// Put implements OutboxStore
+func (f *FileOutboxStore) Put(item OutboxItem) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	items, err := f.load()
+	if err != nil {
+		return err
+	}
+	items[item.ID] = item
+
+	b, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(f.Path, b)
+}
+
+// List implements OutboxStore
+func (f *FileOutboxStore) List() ([]OutboxItem, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	items, err := f.load()
+	if err != nil {
+		return nil, err
+	}
+	list := make([]OutboxItem, 0, len(items))
+	for _, item := range items {
+		list = append(list, item)
+	}
+	return list, nil
+}
+
+// load reads the items of the file, a missing file is empty
+func (f *FileOutboxStore) load() (map[string]OutboxItem, error) {
+	items := map[string]OutboxItem{}
+	b, err := os.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return items, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(b, &items)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected outbox store json:%v", err)
+	}
+	return items, nil
+}
+
+// PayoutOutboxConfig configures a PayoutOutbox
+// zero values are replaced by the defaults
+type PayoutOutboxConfig struct {
+	// PollInterval is the wait between two checks of the pending payouts, 1 second by default
+	PollInterval time.Duration
+	// OnOutcome is called when a payout is sent, failed or parked
+	OnOutcome func(item OutboxItem)
+}
+
+// PayoutOutbox sends the enqueued payouts at most once
+// a payout is marked sending in the store before the request,
+// so a payout with an unknown outcome is parked for reconciliation and never resent automatically
+// only one PayoutOutbox must use a store at a time
+type PayoutOutbox struct {
+	sdk   *SDK
+	store OutboxStore
+	cfg   PayoutOutboxConfig
+
+	mu sync.Mutex
+}
+
+// NewPayoutOutbox creates a PayoutOutbox
+// payouts left sending by a previous run are parked
+func NewPayoutOutbox(s *SDK, store OutboxStore, cfg PayoutOutboxConfig) (*PayoutOutbox, error) {
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = time.Second
+	}
+	o := &PayoutOutbox{sdk: s, store: store, cfg: cfg}
+
+	sending, err := o.List(OutboxSending)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range sending {
+		err = o.outcome(item, OutboxParked, "", "", "interrupted while sending")
+		if err != nil {
+			return nil, err
+		}
+	}
+	return o, nil
+}
+
+// Enqueue validates and signs the payout and stores it as pending
+// returns an error if a payout with the same MerchantOrderID is already enqueued
+func (o *PayoutOutbox) Enqueue(p PayoutOrder) (OutboxItem, error) {
+	r, err := o.sdk.PreparePayout(p)
+	if err != nil {
+		return OutboxItem{}, err
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	_, ok, err := o.store.Get(r.MerchantOrderID)
+	if err != nil {
+		return OutboxItem{}, err
+	}
+	if ok {
+		return OutboxItem{}, fmt.Errorf("merchantOrderID %v already enqueued", r.MerchantOrderID)
+	}
+
+	now := timeNow()
+	item := OutboxItem{
+		ID:        r.MerchantOrderID,
+		State:     OutboxPending,
+		Request:   r,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	return item, o.store.Put(item)
+}
+
+// Get returns the payout by ID
+func (o *PayoutOutbox) Get(id string) (OutboxItem, bool, error) {
+	return o.store.Get(id)
+}
+
+// List returns the payouts in one of the states, all the payouts if none
+// sorted by creation time
+func (o *PayoutOutbox) List(states ...OutboxState) ([]OutboxItem, error) {
+	items, err := o.store.List()
+	if err != nil {
+		return nil, err
+	}
+	var list []OutboxItem
+	for _, item := range items {
+		if len(states) == 0 || containsState(states, item.State) {
+			list = append(list, item)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].CreatedAt.Equal(list[j].CreatedAt) {
+			return list[i].ID < list[j].ID
+		}
+		return list[i].CreatedAt.Before(list[j].CreatedAt)
+	})
+	return list, nil
+}
+
+// Parked returns the payouts waiting for reconciliation
+func (o *PayoutOutbox) Parked() ([]OutboxItem, error) {
+	return o.List(OutboxParked)
+}
+
+// Retry sets a parked payout back to pending
+// to be used only once the payout is known not to have reached Zota
+func (o *PayoutOutbox) Retry(id string) error {
+	return o.move(id, OutboxPending, OutboxParked)
+}
+
+// Cancel cancels a pending or parked payout
+func (o *PayoutOutbox) Cancel(id string) error {
+	return o.move(id, OutboxCancelled, OutboxPending, OutboxParked)
+}
+
+// move changes the state of a payout if it is in one of the from states
+func (o *PayoutOutbox) move(id string, to OutboxState, from ...OutboxState) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	item, ok, err := o.store.Get(id)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("unknown payout %v", id)
+	}
+	if !containsState(from, item.State) {
+		return fmt.Errorf("payout %v is %v", id, item.State)
+	}
+	item.State = to
+	item.UpdatedAt = timeNow()
+	return o.store.Put(item)
+}
+
+// Run sends the pending payouts until ctx is done
+// returns ctx.Err() or the error of the store
+func (o *PayoutOutbox) Run(ctx context.Context) error {
+	for {
+		err := o.SendPending(ctx)
+		if err != nil {
+			return err
+		}
+		if !sleep(ctx, o.cfg.PollInterval) {
+			return ctx.Err()
+		}
+	}
+}
+
+// SendPending sends the pending payouts in creation order
+// a payout being sent is not interrupted when ctx is done
+// returns ctx.Err() or the error of the store
+func (o *PayoutOutbox) SendPending(ctx context.Context) error {
+	pending, err := o.List(OutboxPending)
+	if err != nil {
+		return err
+	}
+	for _, item := range pending {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		err = o.send(item.ID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// send claims and sends a pending payout and records its outcome
+func (o *PayoutOutbox) send(id string) error {
+	o.mu.Lock()
+	item, ok, err := o.store.Get(id)
+	if err != nil || !ok || item.State != OutboxPending {
+		//cancelled meanwhile
+		o.mu.Unlock()
+		return err
+	}
+	item.State = OutboxSending
+	item.Attempts++
+	item.UpdatedAt = timeNow()
+	err = o.store.Put(item)
+	o.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	//the request is not bound to ctx, an interrupted request has an unknown outcome
+	sent := false
+	resp, err := o.sdk.Send(withSentHook(context.Background(), func() { sent = true }), item.Request)
+	switch {
+	case err != nil && !sent && (errors.Is(err, ErrCircuitOpen) || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded)):
+		//not sent yet, e.g. the circuit is open or the rate limiter wait failed
+		return o.outcome(item, OutboxPending, "", "", err.Error())
+	case err != nil && !sent:
+		//never sendable as is, e.g. an invalid SDK or a URL not targeting the ApiBaseURL
+		return o.outcome(item, OutboxFailed, "", "", err.Error())
+	case err != nil:
+		return o.outcome(item, OutboxParked, "", "", err.Error())
+	case resp.StatusCode >= 500:
+		return o.outcome(item, OutboxParked, "", "", fmt.Sprintf("unexpected http status %v", resp.StatusCode))
+	case resp.DryRun:
+		//not sent, parked so it is not marked sent nor retried in a loop
+		return o.outcome(item, OutboxParked, "", "", "dry-run")
+	}
+
+	res, err := resp.PayoutResult()
+	switch {
+	case err != nil:
+		return o.outcome(item, OutboxParked, "", "", err.Error())
+	case res.Code == "200":
+		return o.outcome(item, OutboxSent, res.Code, res.Data.OrderID, "")
+	default:
+		return o.outcome(item, OutboxFailed, res.Code, "", res.Message)
+	}
+}
+
+// outcome stores the outcome of a payout and calls the OnOutcome hook
+func (o *PayoutOutbox) outcome(item OutboxItem, state OutboxState, code string, orderID string, message string) error {
+	o.mu.Lock()
+	item.State = state
+	item.Code = code
+	item.OrderID = orderID
+	item.Error = message
+	item.UpdatedAt = timeNow()
+	err := o.store.Put(item)
+	o.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if state != OutboxPending && o.cfg.OnOutcome != nil {
+		o.cfg.OnOutcome(item)
+	}
+	return nil
+}
+
+// containsState reports whether state is one of states
+func containsState(states []OutboxState, state OutboxState) bool {
+	for _, s := range states {
+		if s == state {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,186 @@
+package zota
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testClock makes timeNow advance by a second on every call
+func testClock() func() {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time {
+		now = now.Add(time.Second)
+		return now
+	}
+	return func() { timeNow = time.Now }
+}
+
+func outboxPayout(merchantOrderID string) PayoutOrder {
+	p := brazilPayoutOrder()
+	p.MerchantOrderID = merchantOrderID
+	return p
+}
+
+func TestPayoutOutbox(t *testing.T) {
+	defer testClock()()
+
+	client := &ClientMockSequence{responses: []string{
+		`{"code":"200","data":{"merchantOrderID":"p-1","orderID":"9001"}}`,
+		`{"code":"400","message":"invalid account"}`,
+		"",
+		`{"code":"200","data":{"merchantOrderID":"p-3","orderID":"9003"}}`,
+	}}
+	var outcomes []string
+	o, err := NewPayoutOutbox(testPollSDK(client), NewMemoryOutboxStore(), PayoutOutboxConfig{
+		OnOutcome: func(item OutboxItem) {
+			outcomes = append(outcomes, item.ID+" "+string(item.State))
+		},
+	})
+	assert.Equal(t, nil, err)
+
+	_, err = o.Enqueue(PayoutOrder{})
+	assert.Equal(t, fmt.Errorf("MerchantOrderID is required"), err)
+
+	for _, id := range []string{"p-1", "p-2", "p-3", "p-4"} {
+		item, err := o.Enqueue(outboxPayout(id))
+		assert.Equal(t, nil, err)
+		assert.Equal(t, OutboxPending, item.State)
+		assert.Equal(t, OperationPayout, item.Request.Operation)
+	}
+	_, err = o.Enqueue(outboxPayout("p-1"))
+	assert.Equal(t, fmt.Errorf("merchantOrderID p-1 already enqueued"), err)
+
+	assert.Equal(t, nil, o.Cancel("p-4"))
+	assert.Equal(t, nil, o.SendPending(context.Background()))
+	assert.Equal(t, 3, client.Calls())
+
+	item, _, _ := o.Get("p-1")
+	assert.Equal(t, OutboxSent, item.State)
+	assert.Equal(t, "9001", item.OrderID)
+	assert.Equal(t, 1, item.Attempts)
+	item, _, _ = o.Get("p-2")
+	assert.Equal(t, OutboxFailed, item.State)
+	assert.Equal(t, "400", item.Code)
+	assert.Equal(t, "invalid account", item.Error)
+
+	parked, err := o.Parked()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(parked))
+	assert.Equal(t, "p-3", parked[0].ID)
+	assert.Equal(t, "do error", parked[0].Error)
+
+	//parked payouts are not resent until retried
+	assert.Equal(t, nil, o.SendPending(context.Background()))
+	assert.Equal(t, 3, client.Calls())
+
+	assert.Equal(t, fmt.Errorf("payout p-1 is sent"), o.Retry("p-1"))
+	assert.Equal(t, fmt.Errorf("unknown payout p-9"), o.Retry("p-9"))
+	assert.Equal(t, nil, o.Retry("p-3"))
+	assert.Equal(t, nil, o.SendPending(context.Background()))
+	item, _, _ = o.Get("p-3")
+	assert.Equal(t, OutboxSent, item.State)
+	assert.Equal(t, 2, item.Attempts)
+	assert.Equal(t, "", item.Error)
+
+	all, _ := o.List()
+	var ids []string
+	for _, item := range all {
+		ids = append(ids, item.ID+" "+string(item.State))
+	}
+	assert.Equal(t, []string{"p-1 sent", "p-2 failed", "p-3 sent", "p-4 cancelled"}, ids)
+	assert.Equal(t, []string{"p-1 sent", "p-2 failed", "p-3 parked", "p-3 sent"}, outcomes)
+}
+
+func TestPayoutOutbox_Ambiguous(t *testing.T) {
+	defer testClock()()
+
+	//a 5xx response is parked
+	status := &ClientMockStatus{StatusCode: 502}
+	sdk := testPollSDK(status)
+	o, _ := NewPayoutOutbox(sdk, NewMemoryOutboxStore(), PayoutOutboxConfig{})
+	o.Enqueue(outboxPayout("p-1"))
+	assert.Equal(t, nil, o.SendPending(context.Background()))
+	item, _, _ := o.Get("p-1")
+	assert.Equal(t, OutboxParked, item.State)
+	assert.Equal(t, "unexpected http status 502", item.Error)
+
+	//an open circuit did not send the payout, it stays pending
+	sdk.CircuitBreaker = NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1})
+	sdk.CircuitBreaker.done(OperationPayout, false, false, true)
+	o.Enqueue(outboxPayout("p-2"))
+	assert.Equal(t, nil, o.SendPending(context.Background()))
+	item, _, _ = o.Get("p-2")
+	assert.Equal(t, OutboxPending, item.State)
+	assert.Equal(t, 1, item.Attempts)
+	assert.Equal(t, 1, status.calls)
+}
+
+func TestPayoutOutbox_NotSent(t *testing.T) {
+	defer testClock()()
+
+	//a transport error may have reached Zota, it is parked
+	client := &ClientMockSequence{responses: []string{""}}
+	sdk := testPollSDK(client)
+	o, _ := NewPayoutOutbox(sdk, NewMemoryOutboxStore(), PayoutOutboxConfig{})
+	o.Enqueue(outboxPayout("p-1"))
+	assert.Equal(t, nil, o.SendPending(context.Background()))
+	item, _, _ := o.Get("p-1")
+	assert.Equal(t, OutboxParked, item.State)
+	assert.Equal(t, "do error", item.Error)
+
+	//a request rejected before sending is failed, not parked
+	o.Enqueue(outboxPayout("p-2"))
+	sdk.ApiBaseURL = LIVE
+	assert.Equal(t, nil, o.SendPending(context.Background()))
+	item, _, _ = o.Get("p-2")
+	assert.Equal(t, OutboxFailed, item.State)
+	assert.Equal(t, "unexpected URL", item.Error)
+	assert.Equal(t, 1, client.Calls())
+}
+
+func TestPayoutOutbox_FileStore(t *testing.T) {
+	defer testClock()()
+
+	store := &FileOutboxStore{Path: filepath.Join(t.TempDir(), "outbox.json")}
+	o, err := NewPayoutOutbox(testPollSDK(nil), store, PayoutOutboxConfig{})
+	assert.Equal(t, nil, err)
+	o.Enqueue(outboxPayout("p-1"))
+	o.Enqueue(outboxPayout("p-2"))
+
+	//simulate a crash while sending p-1
+	item, _, _ := store.Get("p-1")
+	item.State = OutboxSending
+	assert.Equal(t, nil, store.Put(item))
+
+	o, err = NewPayoutOutbox(testPollSDK(nil), &FileOutboxStore{Path: store.Path}, PayoutOutboxConfig{})
+	assert.Equal(t, nil, err)
+	parked, _ := o.Parked()
+	assert.Equal(t, 1, len(parked))
+	assert.Equal(t, "interrupted while sending", parked[0].Error)
+	pending, _ := o.List(OutboxPending)
+	assert.Equal(t, "p-2", pending[0].ID)
+	assert.Equal(t, "503368", pending[0].Request.EndpointID)
+
+	assert.Equal(t, nil, o.Cancel("p-1"))
+	assert.Equal(t, fmt.Errorf("payout p-1 is cancelled"), o.Cancel("p-1"))
+}
+
+func TestPayoutOutbox_Run(t *testing.T) {
+	client := &ClientMockSequence{responses: []string{`{"code":"200","data":{"orderID":"9001"}}`}}
+	o, _ := NewPayoutOutbox(testPollSDK(client), NewMemoryOutboxStore(), PayoutOutboxConfig{PollInterval: 5 * time.Millisecond})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		o.Enqueue(outboxPayout("p-1"))
+	}()
+	assert.Equal(t, context.DeadlineExceeded, o.Run(ctx))
+	item, _, _ := o.Get("p-1")
+	assert.Equal(t, OutboxSent, item.State)
+}
@@ -0,0 +1,177 @@
+package zota
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// PayoutProfile describes which PayoutOrder fields are required
+// for a payout destination and how they have to look like
+// the profile is selected by CustomerCountryCode and OrderCurrency,
+// an empty Country or Currency matches any value
+type PayoutProfile struct {
+	Name     string
+	Country  string
+	Currency string
+
+	// Required lists the PayoutOrder field names which can not be empty
+	Required []string
+	// Formats contains the regular expression a PayoutOrder field must match if set
+	Formats map[string]*regexp.Regexp
+	// Allowed contains the list of values a PayoutOrder field can have if set
+	Allowed map[string][]string
+	// Rules are additional validations of the profile
+	Rules []PayoutRule
+}
+
+// DefaultPayoutProfiles are the built-in payout profiles
+// used when SDK.PayoutProfiles is nil
+var DefaultPayoutProfiles = []PayoutProfile{
+	{
+		Name:     "Brazil",
+		Country:  "BR",
+		Currency: "BRL",
+		Required: []string{"CustomerPersonalID", "CustomerBankAccountType", "CustomerBankBranchDigit", "CustomerBankAccountNumberDigit"},
+		Formats: map[string]*regexp.Regexp{
+			"CustomerBankBranch":             regexp.MustCompile(`^[0-9]{1,5}$`),
+			"CustomerBankBranchDigit":        regexp.MustCompile(`^[0-9A-Za-z]$`),
+			"CustomerBankAccountNumber":      regexp.MustCompile(`^[0-9]{1,20}$`),
+			"CustomerBankAccountNumberDigit": regexp.MustCompile(`^[0-9A-Za-z]$`),
+		},
+		Allowed: map[string][]string{
+			"CustomerBankAccountType": {"checking", "savings"},
+		},
+		Rules: []PayoutRule{PayoutRuleBrazilPersonalID},
+	},
+	{
+		Name:     "China",
+		Country:  "CN",
+		Currency: "CNY",
+		Required: []string{"CustomerBankProvince", "CustomerBankArea"},
+		Allowed: map[string][]string{
+			"CustomerBankProvince": chinaProvinces,
+		},
+	},
+	{
+		Name:     "United States",
+		Country:  "US",
+		Currency: "USD",
+		Required: []string{"CustomerBankRoutingNumber"},
+		Allowed: map[string][]string{
+			"CustomerBankAccountType": {"checking", "savings"},
+		},
+		Rules: []PayoutRule{PayoutRuleABARouting},
+	},
+}
+
+// chinaProvinces are the provincial level divisions of mainland China
+// in pinyin and in chinese characters
+var chinaProvinces = []string{
+	"Anhui", "Beijing", "Chongqing", "Fujian", "Gansu", "Guangdong", "Guangxi", "Guizhou", "Hainan", "Hebei",
+	"Heilongjiang", "Henan", "Hubei", "Hunan", "Inner Mongolia", "Jiangsu", "Jiangxi", "Jilin", "Liaoning",
+	"Ningxia", "Qinghai", "Shaanxi", "Shandong", "Shanghai", "Shanxi", "Sichuan", "Tianjin",
+	"Tibet", "Xinjiang", "Yunnan", "Zhejiang",
+	"安徽", "北京", "重庆", "福建", "甘肃", "广东", "广西", "贵州", "海南", "河北",
+	"黑龙江", "河南", "湖北", "湖南", "内蒙古", "江苏", "江西", "吉林", "辽宁",
+	"宁夏", "青海", "陕西", "山东", "上海", "山西", "四川", "天津",
+	"西藏", "新疆", "云南", "浙江",
+}
+
+// SelectPayoutProfile returns the most specific profile matching
+// the PayoutOrder CustomerCountryCode and OrderCurrency
+// a profile matching both wins over a profile matching the country only,
+// which wins over a profile matching the currency only
+// on a tie the first profile of the list is returned
+func SelectPayoutProfile(profiles []PayoutProfile, p PayoutOrder) (PayoutProfile, bool) {
+	best, bestScore := -1, -1
+	for i, profile := range profiles {
+		score := 0
+		if profile.Country != "" {
+			if !strings.EqualFold(profile.Country, p.CustomerCountryCode) {
+				continue
+			}
+			score += 2
+		}
+		if profile.Currency != "" {
+			if !strings.EqualFold(profile.Currency, p.OrderCurrency) {
+				continue
+			}
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	if best == -1 {
+		return PayoutProfile{}, false
+	}
+	return profiles[best], true
+}
+
+// Validate checks the PayoutOrder against the profile
+// and returns the failed fields as FieldErrors
+func (pp PayoutProfile) Validate(p PayoutOrder) error {
+	var errs FieldErrors
+	r := reflect.ValueOf(p)
+
+	for _, fieldName := range pp.Required {
+		f := r.FieldByName(fieldName)
+		if !f.IsValid() {
+			errs.add(fieldName, "is not a PayoutOrder field")
+			continue
+		}
+		if f.String() == "" {
+			errs.add(fieldName, "is required")
+		}
+	}
+
+	for _, fieldName := range sortedKeys(pp.Formats) {
+		value := r.FieldByName(fieldName)
+		if value.IsValid() && value.String() != "" && !pp.Formats[fieldName].MatchString(value.String()) {
+			errs.add(fieldName, "has invalid format")
+		}
+	}
+
+	for _, fieldName := range sortedKeys(pp.Allowed) {
+		value := r.FieldByName(fieldName)
+		if value.IsValid() && value.String() != "" && !containsFold(pp.Allowed[fieldName], value.String()) {
+			errs.add(fieldName, "is not an allowed value")
+		}
+	}
+
+	for _, rule := range pp.Rules {
+		errs = append(errs, rule(&p)...)
+	}
+
+	return errs.err()
+}
+
+// payoutProfiles returns the profiles used by the SDK
+func (s *SDK) payoutProfiles() []PayoutProfile {
+	if s.PayoutProfiles == nil {
+		return DefaultPayoutProfiles
+	}
+	return s.PayoutProfiles
+}
+
+// validatePayoutProfile selects the profile of the PayoutOrder
+// and validates the order against it
+// returns nil if no profile matches
+func (s *SDK) validatePayoutProfile(p PayoutOrder) error {
+	profile, ok := SelectPayoutProfile(s.payoutProfiles(), p)
+	if !ok {
+		return nil
+	}
+	return profile.Validate(p)
+}
+
+// containsFold reports whether v is present in list ignoring the case
+func containsFold(list []string, v string) bool {
+	for _, s := range list {
+		if strings.EqualFold(s, v) {
+			return true
+		}
+	}
+	return false
+}
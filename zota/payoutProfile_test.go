@@ -0,0 +1,167 @@
+package zota
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectPayoutProfile(t *testing.T) {
+	profiles := []PayoutProfile{
+		{Name: "any"},
+		{Name: "currency", Currency: "USD"},
+		{Name: "country", Country: "US"},
+		{Name: "both", Country: "US", Currency: "USD"},
+		{Name: "both duplicate", Country: "US", Currency: "USD"},
+	}
+
+	tests := map[string]PayoutOrder{
+		"both":     {CustomerCountryCode: "us", OrderCurrency: "usd"},
+		"country":  {CustomerCountryCode: "US", OrderCurrency: "EUR"},
+		"currency": {CustomerCountryCode: "MY", OrderCurrency: "USD"},
+		"any":      {CustomerCountryCode: "MY", OrderCurrency: "MYR"},
+	}
+	for expected, p := range tests {
+		profile, ok := SelectPayoutProfile(profiles, p)
+		assert.True(t, ok)
+		assert.Equal(t, expected, profile.Name)
+	}
+
+	_, ok := SelectPayoutProfile(DefaultPayoutProfiles, PayoutOrder{CustomerCountryCode: "MY", OrderCurrency: "MYR"})
+	assert.False(t, ok)
+}
+
+func TestPayoutProfile_Validate(t *testing.T) {
+	profile := PayoutProfile{
+		Name:     "custom",
+		Required: []string{"CustomerBankCode", "CustomerBankAccountType", "NotAField"},
+		Formats: map[string]*regexp.Regexp{
+			"CustomerBankZipCode": regexp.MustCompile(`^[0-9]{5}$`),
+		},
+		Allowed: map[string][]string{
+			"CustomerBankAccountType": {"checking", "savings"},
+		},
+		Rules: []PayoutRule{PayoutRuleSwiftCode},
+	}
+
+	err := profile.Validate(PayoutOrder{
+		CustomerBankZipCode:     "ABC",
+		CustomerBankAccountType: "business",
+		CustomerBankSwiftCode:   "X",
+	})
+	assert.Equal(t, FieldErrors{
+		{Field: "CustomerBankCode", Message: "is required"},
+		{Field: "NotAField", Message: "is not a PayoutOrder field"},
+		{Field: "CustomerBankZipCode", Message: "has invalid format"},
+		{Field: "CustomerBankAccountType", Message: "is not an allowed value"},
+		{Field: "CustomerBankSwiftCode", Message: "invalid BIC length"},
+	}, err)
+
+	err = profile.Validate(PayoutOrder{
+		CustomerBankCode:        "BBL",
+		CustomerBankZipCode:     "84280",
+		CustomerBankAccountType: "SAVINGS",
+	})
+	assert.Equal(t, FieldErrors{{Field: "NotAField", Message: "is not a PayoutOrder field"}}, err)
+}
+
+func Test_PayoutWithProfiles(t *testing.T) {
+	sdk := SDK{
+		MerchantID:        "API_MERCHANT_ID",
+		MerchantSecretKey: "API_MERCHANT_SECRET_KEY",
+		EndpointID:        "503368",
+		ApiBaseURL:        SANDBOX,
+		HttpClient:        &PClientMockSuccess{},
+	}
+
+	//default brazil profile
+	p := brazilPayoutOrder()
+	p.CustomerBankAccountType = ""
+	p.CustomerBankBranchDigit = "12"
+	_, err := sdk.Payout(p)
+	assert.Equal(t, FieldErrors{
+		{Field: "CustomerBankAccountType", Message: "is required"},
+		{Field: "CustomerBankBranchDigit", Message: "has invalid format"},
+	}, err)
+
+	//default united states profile
+	us := PayoutOrder{
+		MerchantOrderID:           "134e4f443t651",
+		MerchantOrderDesc:         "Test order description",
+		OrderAmount:               "500",
+		OrderCurrency:             "USD",
+		CustomerCountryCode:       "US",
+		CustomerBankAccountNumber: "100200",
+		CustomerBankAccountName:   "John Doe",
+	}
+	_, err = sdk.Payout(us)
+	assert.Equal(t, FieldErrors{{Field: "CustomerBankRoutingNumber", Message: "is required"}}, err)
+
+	us.CustomerBankRoutingNumber = "021000021"
+	res, err := sdk.Payout(us)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "200", res.Code)
+
+	//merchant profiles replace the defaults
+	sdk.PayoutProfiles = []PayoutProfile{{Name: "US", Country: "US", Required: []string{"CustomerBankSwiftCode"}}}
+	_, err = sdk.Payout(us)
+	assert.Equal(t, FieldErrors{{Field: "CustomerBankSwiftCode", Message: "is required"}}, err)
+
+	//empty profiles disable the validation
+	sdk.PayoutProfiles = []PayoutProfile{}
+	_, err = sdk.Payout(p)
+	assert.Equal(t, nil, err)
+}
+
+func Test_PayoutDefaultAllowedValues(t *testing.T) {
+	client := &ClientMockStatus{StatusCode: 200}
+	sdk := testPollSDK(client)
+
+	//brazil account types
+	p := brazilPayoutOrder()
+	p.CustomerBankAccountType = "03"
+	_, err := sdk.Payout(p)
+	assert.Equal(t, FieldErrors{{Field: "CustomerBankAccountType", Message: "is not an allowed value"}}, err)
+
+	//china provinces
+	cn := PayoutOrder{
+		MerchantOrderID:           "134e4f443t651",
+		MerchantOrderDesc:         "Test order description",
+		OrderAmount:               "500",
+		OrderCurrency:             "CNY",
+		CustomerCountryCode:       "CN",
+		CustomerBankAccountNumber: "100200",
+		CustomerBankAccountName:   "John Doe",
+		CustomerBankProvince:      "Bank Province",
+		CustomerBankArea:          "Beijing",
+	}
+	_, err = sdk.Payout(cn)
+	assert.Equal(t, FieldErrors{{Field: "CustomerBankProvince", Message: "is not an allowed value"}}, err)
+
+	//united states account types
+	us := PayoutOrder{
+		MerchantOrderID:           "134e4f443t651",
+		MerchantOrderDesc:         "Test order description",
+		OrderAmount:               "500",
+		OrderCurrency:             "USD",
+		CustomerCountryCode:       "US",
+		CustomerBankAccountNumber: "100200",
+		CustomerBankAccountName:   "John Doe",
+		CustomerBankRoutingNumber: "021000021",
+		CustomerBankAccountType:   "business",
+	}
+	_, err = sdk.Payout(us)
+	assert.Equal(t, FieldErrors{{Field: "CustomerBankAccountType", Message: "is not an allowed value"}}, err)
+
+	//nothing is sent for a rejected payout
+	assert.Equal(t, 0, client.calls)
+
+	//the allowed values are matched ignoring the case
+	p.CustomerBankAccountType = "Savings"
+	assert.Equal(t, nil, DefaultPayoutProfiles[0].Validate(p))
+	cn.CustomerBankProvince = "guangdong"
+	assert.Equal(t, nil, DefaultPayoutProfiles[1].Validate(cn))
+	cn.CustomerBankProvince = "广东"
+	assert.Equal(t, nil, DefaultPayoutProfiles[1].Validate(cn))
+}
@@ -0,0 +1,83 @@
+package zota
+
+import (
+	"errors"
+
+	"github.com/zota/go-sdk/zota/bankvalidate"
+)
+
+// PayoutRule is an opt-in validation of a PayoutOrder
+// set in SDK.PayoutRules and applied before the payout request is sent
+// returns the failed fields or nil
+type PayoutRule func(p *PayoutOrder) FieldErrors
+
+// PayoutRuleIBAN validates CustomerBankAccountNumber as an IBAN
+var PayoutRuleIBAN PayoutRule = func(p *PayoutOrder) FieldErrors {
+	return checkPayoutField("CustomerBankAccountNumber", p.CustomerBankAccountNumber, bankvalidate.IBAN)
+}
+
+// PayoutRuleSwiftCode validates CustomerBankSwiftCode as a SWIFT/BIC code if set
+var PayoutRuleSwiftCode PayoutRule = func(p *PayoutOrder) FieldErrors {
+	if p.CustomerBankSwiftCode == "" {
+		return nil
+	}
+	return checkPayoutField("CustomerBankSwiftCode", p.CustomerBankSwiftCode, bankvalidate.BIC)
+}
+
+// PayoutRuleABARouting validates CustomerBankRoutingNumber as an ABA routing number if set
+var PayoutRuleABARouting PayoutRule = func(p *PayoutOrder) FieldErrors {
+	if p.CustomerBankRoutingNumber == "" {
+		return nil
+	}
+	return checkPayoutField("CustomerBankRoutingNumber", p.CustomerBankRoutingNumber, bankvalidate.ABARouting)
+}
+
+// PayoutRuleBrazilPersonalID validates CustomerPersonalID as a CPF or CNPJ if set
+var PayoutRuleBrazilPersonalID PayoutRule = func(p *PayoutOrder) FieldErrors {
+	if p.CustomerPersonalID == "" {
+		return nil
+	}
+	return checkPayoutField("CustomerPersonalID", p.CustomerPersonalID, bankvalidate.BrazilPersonalID)
+}
+
+// PayoutRuleBrazilBankAccount validates the branch and account check digits
+// of the Brazilian bank set in CustomerBankCode
+// the failures are reported under the field that failed, e.g. CustomerBankBranchDigit
+var PayoutRuleBrazilBankAccount PayoutRule = func(p *PayoutOrder) FieldErrors {
+	err := bankvalidate.BrazilBankAccount(p.CustomerBankCode, p.CustomerBankBranch, p.CustomerBankBranchDigit, p.CustomerBankAccountNumber, p.CustomerBankAccountNumberDigit)
+	if err == nil {
+		return nil
+	}
+	field := "CustomerBankAccountNumber"
+	var accountErr *bankvalidate.BrazilAccountError
+	if errors.As(err, &accountErr) {
+		field = brazilAccountFields[accountErr.Field]
+	}
+	return FieldErrors{{Field: field, Message: err.Error()}}
+}
+
+// brazilAccountFields are the PayoutOrder fields of the parts of a Brazilian bank account
+var brazilAccountFields = map[bankvalidate.BrazilAccountField]string{
+	bankvalidate.BrazilBranch:       "CustomerBankBranch",
+	bankvalidate.BrazilBranchDigit:  "CustomerBankBranchDigit",
+	bankvalidate.BrazilAccount:      "CustomerBankAccountNumber",
+	bankvalidate.BrazilAccountDigit: "CustomerBankAccountNumberDigit",
+}
+
+// checkPayoutField runs a bankvalidate check on a field value
+func checkPayoutField(field string, value string, check func(string) error) FieldErrors {
+	if err := check(value); err != nil {
+		return FieldErrors{{Field: field, Message: err.Error()}}
+	}
+	return nil
+}
+
+// applyRules runs all rules on the PayoutOrder
+// and returns the failed fields of all of them
+func (d *PayoutOrder) applyRules(rules []PayoutRule) error {
+	var errs FieldErrors
+	for _, rule := range rules {
+		errs = append(errs, rule(d)...)
+	}
+	return errs.err()
+}
@@ -0,0 +1,94 @@
+package zota
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// brazilPayoutOrder returns a valid brazilian PayoutOrder
+func brazilPayoutOrder() PayoutOrder {
+	return PayoutOrder{
+		MerchantOrderID:                "134e4f443t651",
+		MerchantOrderDesc:              "Test order description",
+		OrderAmount:                    "500",
+		OrderCurrency:                  "BRL",
+		CustomerEmail:                  "customer@email-address.com",
+		CustomerBankCode:               "001",
+		CustomerBankAccountNumber:      "12345",
+		CustomerBankAccountNumberDigit: "5",
+		CustomerBankAccountType:        "checking",
+		CustomerBankAccountName:        "John Doe",
+		CustomerBankBranch:             "1234",
+		CustomerBankBranchDigit:        "3",
+		CustomerCountryCode:            "BR",
+		CustomerPersonalID:             "529.982.247-25",
+		CustomerBankSwiftCode:          "BRASBRRJ",
+	}
+}
+
+func Test_PayoutRules(t *testing.T) {
+	rules := []PayoutRule{PayoutRuleSwiftCode, PayoutRuleABARouting, PayoutRuleBrazilPersonalID, PayoutRuleBrazilBankAccount}
+
+	p := brazilPayoutOrder()
+	assert.Equal(t, nil, p.applyRules(rules))
+	assert.Equal(t, nil, p.applyRules(nil))
+
+	p.CustomerPersonalID = "529.982.247-26"
+	p.CustomerBankSwiftCode = "BRAS"
+	p.CustomerBankRoutingNumber = "021000022"
+	p.CustomerBankAccountNumberDigit = "6"
+	assert.Equal(t, FieldErrors{
+		{Field: "CustomerBankSwiftCode", Message: "invalid BIC length"},
+		{Field: "CustomerBankRoutingNumber", Message: "invalid ABA routing number checksum"},
+		{Field: "CustomerPersonalID", Message: "invalid CPF check digits"},
+		{Field: "CustomerBankAccountNumberDigit", Message: "invalid account check digit for Banco do Brasil"},
+	}, p.applyRules(rules))
+}
+
+func Test_PayoutRuleBrazilBankAccount(t *testing.T) {
+	rules := []PayoutRule{PayoutRuleBrazilBankAccount}
+
+	//the failures are reported under the field that failed
+	p := brazilPayoutOrder()
+	p.CustomerBankBranchDigit = "4"
+	assert.Equal(t, FieldErrors{{Field: "CustomerBankBranchDigit", Message: "invalid branch check digit for Banco do Brasil"}}, p.applyRules(rules))
+
+	p = brazilPayoutOrder()
+	p.CustomerBankBranch = "12345"
+	assert.Equal(t, FieldErrors{{Field: "CustomerBankBranch", Message: "value must be up to 4 digits"}}, p.applyRules(rules))
+
+	p = brazilPayoutOrder()
+	p.CustomerBankAccountNumber = "12a45"
+	assert.Equal(t, FieldErrors{{Field: "CustomerBankAccountNumber", Message: "value must be up to 8 digits"}}, p.applyRules(rules))
+}
+
+func Test_PayoutRuleIBAN(t *testing.T) {
+	p := brazilPayoutOrder()
+	p.CustomerBankAccountNumber = "DE89 3704 0044 0532 0130 00"
+	assert.Equal(t, FieldErrors(nil), PayoutRuleIBAN(&p))
+
+	p.CustomerBankAccountNumber = "100200"
+	assert.Equal(t, FieldErrors{{Field: "CustomerBankAccountNumber", Message: "unsupported IBAN country 10"}}, PayoutRuleIBAN(&p))
+}
+
+func Test_PayoutWithRules(t *testing.T) {
+	sdk := SDK{
+		MerchantID:        "API_MERCHANT_ID",
+		MerchantSecretKey: "API_MERCHANT_SECRET_KEY",
+		EndpointID:        "503368",
+		ApiBaseURL:        SANDBOX,
+		HttpClient:        &PClientMockSuccess{},
+		PayoutRules:       []PayoutRule{PayoutRuleBrazilPersonalID},
+	}
+
+	p := brazilPayoutOrder()
+	res, err := sdk.Payout(p)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "200", res.Code)
+
+	p.CustomerPersonalID = "123"
+	res, err = sdk.Payout(p)
+	assert.Equal(t, FieldErrors{{Field: "CustomerPersonalID", Message: "CPF must be 11 digits"}}, err)
+	assert.Equal(t, PayoutResult{}, res)
+}
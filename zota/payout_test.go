@@ -98,6 +98,7 @@ func Test_Payout(t *testing.T) {
 					MerchantOrderID: "QvE8dZshpKhaOmHY",
 					OrderID:         "8b3a6b89697e8ac8f45d964bcc90c7ba41764acd",
 				},
+				EndpointID: "503368",
 			},
 			expectedError: nil,
 			mockSDK: SDK{
@@ -117,7 +118,7 @@ func Test_Payout(t *testing.T) {
 				CustomerLastName:               "Doe",
 				CustomerPhone:                  "+1 420-100-1000",
 				CustomerIP:                     "127.0.0.1",
-				CustomerBankCode:               "BBL",
+				CustomerBankCode:               "MBB",
 				CallbackURL:                    "https://some.endpoint/callback",
 				CheckoutURL:                    "https://some.endpoint/checkout",
 				CustomerBankAccountNumber:      "100200",
@@ -140,8 +141,9 @@ func Test_Payout(t *testing.T) {
 			// --------------------Test Error Api response----------------------
 			name: "Payout Request API Error",
 			expectedPayoutResult: PayoutResult{
-				Code:    "400",
-				Message: "bad request",
+				Code:       "400",
+				Message:    "bad request",
+				EndpointID: "503368",
 			},
 			expectedError: nil,
 			mockSDK: SDK{
@@ -161,7 +163,7 @@ func Test_Payout(t *testing.T) {
 				CustomerLastName:               "Doe",
 				CustomerPhone:                  "+1 420-100-1000",
 				CustomerIP:                     "127.0.0.1",
-				CustomerBankCode:               "BBL",
+				CustomerBankCode:               "MBB",
 				CallbackURL:                    "https://some.endpoint/callback",
 				CheckoutURL:                    "https://some.endpoint/checkout",
 				CustomerBankAccountNumber:      "100200",
@@ -205,7 +207,7 @@ func Test_Payout(t *testing.T) {
 				CustomerLastName:               "Doe",
 				CustomerPhone:                  "+1 420-100-1000",
 				CustomerIP:                     "127.0.0.1",
-				CustomerBankCode:               "BBL",
+				CustomerBankCode:               "MBB",
 				CallbackURL:                    "https://some.endpoint/callback",
 				CheckoutURL:                    "https://some.endpoint/checkout",
 				CustomerBankAccountNumber:      "100200",
@@ -246,7 +248,7 @@ func Test_Payout(t *testing.T) {
 				CustomerLastName:               "Doe",
 				CustomerPhone:                  "+1 420-100-1000",
 				CustomerIP:                     "127.0.0.1",
-				CustomerBankCode:               "BBL",
+				CustomerBankCode:               "MBB",
 				CallbackURL:                    "https://some.endpoint/callback",
 				CheckoutURL:                    "https://some.endpoint/checkout",
 				CustomerBankAccountNumber:      "100200",
@@ -335,7 +337,7 @@ func TestPayoutResult_SetMockResponse(t *testing.T) {
 		CustomerLastName:               "Doe",
 		CustomerPhone:                  "+1 420-100-1000",
 		CustomerIP:                     "127.0.0.1",
-		CustomerBankCode:               "BBL",
+		CustomerBankCode:               "MBB",
 		CallbackURL:                    "https://some.endpoint/callback",
 		CheckoutURL:                    "https://some.endpoint/checkout",
 		CustomerBankAccountNumber:      "100200",
@@ -369,7 +371,7 @@ func TestPayoutResult_SetMockResponse(t *testing.T) {
 		CustomerLastName:               "Doe",
 		CustomerPhone:                  "+1 420-100-1000",
 		CustomerIP:                     "127.0.0.1",
-		CustomerBankCode:               "BBL",
+		CustomerBankCode:               "MBB",
 		CallbackURL:                    "https://some.endpoint/callback",
 		CheckoutURL:                    "https://some.endpoint/checkout",
 		CustomerBankAccountNumber:      "100200",
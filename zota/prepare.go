@@ -0,0 +1,203 @@
+package zota
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"runtime"
+	"strings"
+)
+
+// PreparedRequest represents a validated and signed request to Zota API
+// it can be serialized, persisted and sent later with Send, possibly by another process
+// order status and orders report requests are signed with a timestamp
+// and should be sent shortly after being prepared
+type PreparedRequest struct {
+	Operation       Operation         `json:"operation"`
+	EndpointID      string            `json:"endpointID,omitempty"`
+	MerchantOrderID string            `json:"merchantOrderID,omitempty"`
+	Method          string            `json:"method"`
+	URL             string            `json:"url"`
+	Headers         map[string]string `json:"headers"`
+	Body            json.RawMessage   `json:"body,omitempty"`
+	Signature       string            `json:"signature"`
+}
+
+// PreparedResponse represents the raw response of a PreparedRequest
+// DryRun is set on the synthetic response of a request not sent
+type PreparedResponse struct {
+	Operation  Operation
+	EndpointID string
+	StatusCode int
+	Body       []byte
+	DryRun     bool
+}
+
+// newPreparedRequest creates a PreparedRequest with the SDK headers
+func newPreparedRequest(op Operation, endpointID string, merchantOrderID string, method string, url string, body []byte, signature string) PreparedRequest {
+	return PreparedRequest{
+		Operation:       op,
+		EndpointID:      endpointID,
+		MerchantOrderID: merchantOrderID,
+		Method:          method,
+		URL:             url,
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+			"User-Agent":   fmt.Sprintf("Zota Go SDK %v(%v; %v; %v)", VERSION, runtime.GOOS, runtime.GOARCH, runtime.Version()),
+		},
+		Body:      body,
+		Signature: signature,
+	}
+}
+
+// validate the instance of PreparedRequest
+// the URL must target the ApiBaseURL of the SDK
+func (r PreparedRequest) validate(apiBaseURL string) error {
+	if r.Operation == "" {
+		return fmt.Errorf("Operation is required")
+	}
+	if r.Method == "" {
+		return fmt.Errorf("Method is required")
+	}
+	if r.URL == "" {
+		return fmt.Errorf("URL is required")
+	}
+	if !strings.HasPrefix(r.URL, apiBaseURL+"/") {
+		return fmt.Errorf("unexpected URL")
+	}
+	return nil
+}
+
+// Send sends a PreparedRequest to Zota API
+// the request waits for the RateLimiter and goes through the CircuitBreaker
+// in dry-run the request is logged and not sent, see DryRunMode
+// returns the raw response, see the PreparedResponse methods to decode it
+func (s *SDK) Send(ctx context.Context, r PreparedRequest) (resp PreparedResponse, err error) {
+
+	stream, err := s.sendStream(ctx, r)
+	if err != nil {
+		return
+	}
+	defer stream.Body.Close()
+
+	body, err := ioutil.ReadAll(stream.Body)
+	if err != nil {
+		return
+	}
+
+	resp = PreparedResponse{
+		Operation:  r.Operation,
+		EndpointID: r.EndpointID,
+		StatusCode: stream.StatusCode,
+		Body:       body,
+		DryRun:     stream.DryRun,
+	}
+	return
+}
+
+// streamResponse is the response of a PreparedRequest with the body not read
+type streamResponse struct {
+	StatusCode int
+	Body       io.ReadCloser
+	DryRun     bool
+}
+
+// sendStream sends a PreparedRequest like Send and returns the response
+// with the body not read, the body must be closed
+func (s *SDK) sendStream(ctx context.Context, r PreparedRequest) (resp streamResponse, err error) {
+
+	//validate that SDK is properly initialized
+	err = s.validate()
+	if err != nil {
+		return
+	}
+
+	//validate that PreparedRequest targets the SDK ApiBaseURL
+	err = r.validate(s.ApiBaseURL)
+	if err != nil {
+		return
+	}
+
+	//log and skip the request in dry-run
+	if s.dryRun(ctx, r.Operation) {
+		dry := s.dryRunResponse(r)
+		resp = streamResponse{StatusCode: dry.StatusCode, Body: ioutil.NopCloser(bytes.NewReader(dry.Body)), DryRun: true}
+		return
+	}
+
+	//fail fast while the circuit is open, without waiting for a token
+	err = s.CircuitBreaker.check(r.Operation)
+	if err != nil {
+		return
+	}
+
+	//wait for the rate limiter
+	err = s.RateLimiter.Wait(ctx, r.Operation, r.EndpointID)
+	if err != nil {
+		return
+	}
+
+	httpResp, err := s.httpStream(ctx, r)
+	if err != nil {
+		return
+	}
+	resp = streamResponse{StatusCode: httpResp.StatusCode, Body: httpResp.Body}
+	return
+}
+
+// DepositResult decodes the response of a deposit request
+func (r PreparedResponse) DepositResult() (res DepositResult, err error) {
+	err = json.Unmarshal(r.Body, &res)
+	if err != nil {
+		err = fmt.Errorf("json Unmarshal err:%v", err)
+		return
+	}
+	res.EndpointID = r.EndpointID
+	res.DryRun = r.DryRun
+	return
+}
+
+// PayoutResult decodes the response of a payout request
+func (r PreparedResponse) PayoutResult() (res PayoutResult, err error) {
+	err = json.Unmarshal(r.Body, &res)
+	if err != nil {
+		err = fmt.Errorf("json Unmarshal err:%v", err)
+		return
+	}
+	res.EndpointID = r.EndpointID
+	res.DryRun = r.DryRun
+	return
+}
+
+// OrderStatusResult decodes the response of an order status request
+func (r PreparedResponse) OrderStatusResult() (res OrderStatusResult, err error) {
+	err = json.Unmarshal(r.Body, &res)
+	if err != nil {
+		err = fmt.Errorf("json Unmarshal err:%v", err)
+		return
+	}
+	res.DryRun = r.DryRun
+	return
+}
+
+// OrdersReportResult decodes the response of an orders report request
+// a successful response is the csv report
+func (r PreparedResponse) OrdersReportResult() (res OrdersReportResult, err error) {
+	if r.StatusCode != 200 {
+		err = json.Unmarshal(r.Body, &res)
+		if err != nil {
+			err = fmt.Errorf("json Unmarshal err:%v", err)
+			return
+		}
+		return
+	}
+	res = OrdersReportResult{
+		Code:         "200",
+		OrdersReport: string(r.Body),
+		DryRun:       r.DryRun,
+	}
+	return
+}
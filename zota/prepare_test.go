@@ -0,0 +1,153 @@
+package zota
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testDepositOrder() DepositOrder {
+	return DepositOrder{
+		MerchantOrderID:     "134",
+		MerchantOrderDesc:   "Test order description",
+		OrderAmount:         "500",
+		OrderCurrency:       "USD",
+		CustomerEmail:       "customer@email-address.com",
+		CustomerLastName:    "Doe",
+		CustomerAddress:     "The Swan, Jungle St. 108",
+		CustomerCountryCode: "US",
+		CustomerCity:        "Los Angeles",
+		CustomerZipCode:     "84280",
+		CustomerPhone:       "+1 420-100-1000",
+		CustomerIP:          "134.201.250.130",
+		RedirectURL:         "https://www.example-merchant.com/payment-return/",
+		CheckoutURL:         "https://www.example-merchant.com/account/deposit/",
+	}
+}
+
+func TestSDK_PrepareDeposit(t *testing.T) {
+	sdk := testPollSDK(nil)
+
+	_, err := sdk.PrepareDeposit(DepositOrder{})
+	assert.Equal(t, fmt.Errorf("MerchantOrderID is required"), err)
+
+	r, err := sdk.PrepareDeposit(testDepositOrder())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, OperationDeposit, r.Operation)
+	assert.Equal(t, "503368", r.EndpointID)
+	assert.Equal(t, "134", r.MerchantOrderID)
+	assert.Equal(t, http.MethodPost, r.Method)
+	assert.Equal(t, SANDBOX+"/api/v1/deposit/request/503368/", r.URL)
+	assert.Equal(t, "application/json", r.Headers["Content-Type"])
+	assert.Equal(t, sdk.sign("503368", "134", "500", "customer@email-address.com"), r.Signature)
+
+	var body DepositOrder
+	assert.Equal(t, nil, json.Unmarshal(r.Body, &body))
+	assert.Equal(t, r.Signature, body.Signature)
+
+	//the prepared request survives a json round trip and is sent by another SDK instance
+	b, err := json.Marshal(r)
+	assert.Equal(t, nil, err)
+	assert.True(t, strings.Contains(string(b), `"body":{"merchantOrderID":"134"`))
+	var stored PreparedRequest
+	assert.Equal(t, nil, json.Unmarshal(b, &stored))
+	assert.Equal(t, r, stored)
+
+	client := &ClientMockCaptureURL{}
+	resp, err := testPollSDK(client).Send(context.Background(), stored)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, r.URL, client.url)
+	assert.Equal(t, string(r.Body), client.body)
+	assert.Equal(t, 200, resp.StatusCode)
+
+	res, err := resp.DepositResult()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "200", res.Code)
+	assert.Equal(t, "2", res.Data.OrderID)
+	assert.Equal(t, "503368", res.EndpointID)
+}
+
+func TestSDK_PreparePayout(t *testing.T) {
+	sdk := testPollSDK(nil)
+
+	_, err := sdk.PreparePayout(PayoutOrder{})
+	assert.Equal(t, fmt.Errorf("MerchantOrderID is required"), err)
+
+	p := brazilPayoutOrder()
+	r, err := sdk.PreparePayout(p)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, OperationPayout, r.Operation)
+	assert.Equal(t, SANDBOX+"/api/v1/payout/request/503368/", r.URL)
+	assert.Equal(t, sdk.sign("503368", p.MerchantOrderID, p.OrderAmount, p.CustomerEmail, p.CustomerBankAccountNumber), r.Signature)
+
+	client := &ClientMockCaptureURL{}
+	resp, err := testPollSDK(client).Send(context.Background(), r)
+	assert.Equal(t, nil, err)
+	res, err := resp.PayoutResult()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "503368", res.EndpointID)
+}
+
+func TestSDK_PrepareOrderStatus(t *testing.T) {
+	sdk := testPollSDK(nil)
+
+	r, err := sdk.PrepareOrderStatus(OrderStatus{MerchantOrderID: "m-1", OrderID: "1"})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, OperationOrderStatus, r.Operation)
+	assert.Equal(t, http.MethodGet, r.Method)
+	assert.True(t, strings.HasPrefix(r.URL, SANDBOX+"/api/v1/query/order-status/?"))
+	assert.True(t, strings.Contains(r.URL, "signature="+r.Signature))
+	assert.Nil(t, r.Body)
+
+	resp, err := testPollSDK(&ClientMockSequence{responses: []string{statusResponse(StatusApproved)}}).Send(context.Background(), r)
+	assert.Equal(t, nil, err)
+	res, err := resp.OrderStatusResult()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, StatusApproved, res.Status)
+}
+
+func TestSDK_PrepareOrdersReport(t *testing.T) {
+	r, err := testPollSDK(nil).PrepareOrdersReport(OrdersReport{DateType: "created", FromDate: "2021-01-01", ToDate: "2021-01-02"})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, OperationOrdersReport, r.Operation)
+	assert.True(t, strings.HasPrefix(r.URL, SANDBOX+"/api/v1/query/orders-report/csv/?"))
+
+	res, err := PreparedResponse{StatusCode: 200, Body: []byte("a,b\n1,2\n")}.OrdersReportResult()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, OrdersReportResult{Code: "200", OrdersReport: "a,b\n1,2\n"}, res)
+
+	res, err = PreparedResponse{StatusCode: 400, Body: []byte(`{"code":"400","message":"bad request"}`)}.OrdersReportResult()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, OrdersReportResult{Code: "400", Message: "bad request"}, res)
+}
+
+func TestSDK_Send(t *testing.T) {
+	sdk := testPollSDK(&ClientMockCaptureURL{})
+	r, _ := sdk.PrepareDeposit(testDepositOrder())
+
+	tests := map[string]func(r *PreparedRequest){
+		"Operation is required": func(r *PreparedRequest) { r.Operation = "" },
+		"Method is required":    func(r *PreparedRequest) { r.Method = "" },
+		"URL is required":       func(r *PreparedRequest) { r.URL = "" },
+		"unexpected URL":        func(r *PreparedRequest) { r.URL = "https://attacker.example.com/api/v1/deposit/request/503368/" },
+	}
+	for expected, change := range tests {
+		t.Run(expected, func(t *testing.T) {
+			bad := r
+			change(&bad)
+			_, err := sdk.Send(context.Background(), bad)
+			assert.Equal(t, fmt.Errorf(expected), err)
+		})
+	}
+
+	//the request prepared for the sandbox is not sent to live
+	live := testPollSDK(&ClientMockCaptureURL{})
+	live.ApiBaseURL = LIVE
+	_, err := live.Send(context.Background(), r)
+	assert.Equal(t, fmt.Errorf("unexpected URL"), err)
+}
@@ -0,0 +1,231 @@
+package zota
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Priority represents the priority class of a request waiting for the RateLimiter
+// interactive requests are served before batch requests
+type Priority int
+
+const (
+	PriorityInteractive Priority = iota
+	PriorityBatch
+)
+
+// priorityKey is the context key of the request Priority
+type priorityKey struct{}
+
+// WithPriority returns a context carrying the Priority of the requests made with it
+// requests without a Priority are interactive
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityKey{}, p)
+}
+
+// PriorityFrom returns the Priority carried by ctx
+func PriorityFrom(ctx context.Context) Priority {
+	p, _ := ctx.Value(priorityKey{}).(Priority)
+	return p
+}
+
+// withDefaultPriority sets the Priority of ctx if it has none
+func withDefaultPriority(ctx context.Context, p Priority) context.Context {
+	if _, ok := ctx.Value(priorityKey{}).(Priority); ok {
+		return ctx
+	}
+	return WithPriority(ctx, p)
+}
+
+// RateLimit is the limit of a token bucket
+// Rate is the number of requests per second, Burst the bucket size
+// Burst defaults to Rate rounded up
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// RateLimiterConfig configures a RateLimiter
+// a request waits for the limit of its operation and then for the limit of its EndpointID
+// operations and endpoints without a limit are not limited
+type RateLimiterConfig struct {
+	Operations map[Operation]RateLimit
+	Endpoints  map[string]RateLimit
+	// OnWait is called after every request waited for the limiter, e.g. to export metrics
+	OnWait func(op Operation, endpointID string, p Priority, d time.Duration)
+}
+
+// WaitStats represents the time spent waiting for the RateLimiter
+type WaitStats struct {
+	Requests  int64
+	Waited    int64
+	TotalWait time.Duration
+	MaxWait   time.Duration
+}
+
+// RateLimiter is a client-side token bucket limiter
+// with separate limits per operation and per EndpointID
+// it is safe for concurrent use
+type RateLimiter struct {
+	cfg RateLimiterConfig
+
+	mu         sync.Mutex
+	operations map[Operation]*tokenBucket
+	endpoints  map[string]*tokenBucket
+	stats      map[Operation]map[Priority]WaitStats
+}
+
+// NewRateLimiter creates a RateLimiter
+func NewRateLimiter(cfg RateLimiterConfig) *RateLimiter {
+	l := &RateLimiter{
+		cfg:        cfg,
+		operations: map[Operation]*tokenBucket{},
+		endpoints:  map[string]*tokenBucket{},
+		stats:      map[Operation]map[Priority]WaitStats{},
+	}
+	for op, limit := range cfg.Operations {
+		if limit.Rate > 0 {
+			l.operations[op] = newTokenBucket(limit)
+		}
+	}
+	for endpointID, limit := range cfg.Endpoints {
+		if limit.Rate > 0 {
+			l.endpoints[endpointID] = newTokenBucket(limit)
+		}
+	}
+	return l
+}
+
+// Wait blocks until the request is allowed by the limits of the operation and the EndpointID
+// the Priority of the request is read from ctx, see WithPriority
+// returns ctx.Err() if ctx is done before, a nil RateLimiter never waits
+func (l *RateLimiter) Wait(ctx context.Context, op Operation, endpointID string) error {
+	if l == nil {
+		return nil
+	}
+	p := PriorityFrom(ctx)
+	start := time.Now()
+
+	err := l.operations[op].wait(ctx, p)
+	if err == nil && endpointID != "" {
+		err = l.endpoints[endpointID].wait(ctx, p)
+	}
+	if err != nil {
+		return err
+	}
+
+	waited := time.Since(start)
+	l.record(op, p, waited)
+	if l.cfg.OnWait != nil {
+		l.cfg.OnWait(op, endpointID, p, waited)
+	}
+	return nil
+}
+
+// Stats returns the wait statistics per operation and Priority
+func (l *RateLimiter) Stats() map[Operation]map[Priority]WaitStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := make(map[Operation]map[Priority]WaitStats, len(l.stats))
+	for op, byPriority := range l.stats {
+		stats[op] = make(map[Priority]WaitStats, len(byPriority))
+		for p, s := range byPriority {
+			stats[op][p] = s
+		}
+	}
+	return stats
+}
+
+// record adds a wait to the statistics
+func (l *RateLimiter) record(op Operation, p Priority, waited time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.stats[op] == nil {
+		l.stats[op] = map[Priority]WaitStats{}
+	}
+	s := l.stats[op][p]
+	s.Requests++
+	//waits under a millisecond are the cost of the limiter itself
+	if waited >= time.Millisecond {
+		s.Waited++
+		s.TotalWait += waited
+		if waited > s.MaxWait {
+			s.MaxWait = waited
+		}
+	}
+	l.stats[op][p] = s
+}
+
+// tokenBucket is a token bucket serving the interactive waiters first
+type tokenBucket struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   float64
+	tokens  float64
+	last    time.Time
+	waiting map[Priority]int
+}
+
+// newTokenBucket creates a full tokenBucket
+func newTokenBucket(limit RateLimit) *tokenBucket {
+	burst := float64(limit.Burst)
+	if burst < 1 {
+		burst = math.Max(1, math.Ceil(limit.Rate))
+	}
+	return &tokenBucket{
+		rate:    limit.Rate,
+		burst:   burst,
+		tokens:  burst,
+		last:    time.Now(),
+		waiting: map[Priority]int{},
+	}
+}
+
+// wait takes a token from the bucket
+// batch waiters do not take a token while interactive waiters are queued
+// a nil tokenBucket never waits
+func (b *tokenBucket) wait(ctx context.Context, p Priority) error {
+	if b == nil {
+		return nil
+	}
+
+	queued := false
+	defer func() {
+		if queued {
+			b.mu.Lock()
+			b.waiting[p]--
+			b.mu.Unlock()
+		}
+	}()
+
+	for {
+		b.mu.Lock()
+		now := time.Now()
+		b.tokens = math.Min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+		b.last = now
+
+		preempted := p != PriorityInteractive && b.waiting[PriorityInteractive] > 0
+		if b.tokens >= 1 && !preempted {
+			b.tokens--
+			b.mu.Unlock()
+			return nil
+		}
+		if !queued {
+			b.waiting[p]++
+			queued = true
+		}
+		delay := time.Duration((1 - b.tokens) / b.rate * float64(time.Second))
+		if delay < time.Millisecond {
+			delay = time.Millisecond
+		}
+		b.mu.Unlock()
+
+		if !sleep(ctx, delay) {
+			return ctx.Err()
+		}
+	}
+}
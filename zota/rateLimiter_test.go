@@ -0,0 +1,122 @@
+package zota
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriority(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, PriorityInteractive, PriorityFrom(ctx))
+	assert.Equal(t, PriorityBatch, PriorityFrom(WithPriority(ctx, PriorityBatch)))
+	assert.Equal(t, PriorityBatch, PriorityFrom(withDefaultPriority(ctx, PriorityBatch)))
+	assert.Equal(t, PriorityInteractive, PriorityFrom(withDefaultPriority(WithPriority(ctx, PriorityInteractive), PriorityBatch)))
+}
+
+func TestRateLimiter_Wait(t *testing.T) {
+	var nilLimiter *RateLimiter
+	assert.Equal(t, nil, nilLimiter.Wait(context.Background(), OperationDeposit, "1"))
+
+	var waits []string
+	l := NewRateLimiter(RateLimiterConfig{
+		Operations: map[Operation]RateLimit{OperationDeposit: {Rate: 20, Burst: 1}},
+		Endpoints:  map[string]RateLimit{"2": {Rate: 10}},
+		OnWait: func(op Operation, endpointID string, p Priority, d time.Duration) {
+			waits = append(waits, string(op)+"/"+endpointID)
+		},
+	})
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, nil, l.Wait(context.Background(), OperationDeposit, "1"))
+	}
+	assert.True(t, time.Since(start) >= 90*time.Millisecond)
+
+	//operations without a limit are not limited
+	start = time.Now()
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, nil, l.Wait(context.Background(), OperationPayout, ""))
+	}
+	assert.True(t, time.Since(start) < 50*time.Millisecond)
+
+	//endpoint limit on top of the operation limit
+	for i := 0; i < 12; i++ {
+		assert.Equal(t, nil, l.Wait(context.Background(), OperationPayout, "2"))
+	}
+
+	stats := l.Stats()
+	assert.Equal(t, int64(3), stats[OperationDeposit][PriorityInteractive].Requests)
+	assert.Equal(t, int64(2), stats[OperationDeposit][PriorityInteractive].Waited)
+	assert.True(t, stats[OperationDeposit][PriorityInteractive].TotalWait >= 90*time.Millisecond)
+	assert.Equal(t, int64(22), stats[OperationPayout][PriorityInteractive].Requests)
+	assert.True(t, stats[OperationPayout][PriorityInteractive].MaxWait >= 50*time.Millisecond)
+	assert.Equal(t, 25, len(waits))
+	assert.Equal(t, "deposit/1", waits[0])
+	assert.Equal(t, "payout/2", waits[24])
+}
+
+func TestRateLimiter_WaitContext(t *testing.T) {
+	l := NewRateLimiter(RateLimiterConfig{Operations: map[Operation]RateLimit{OperationOrderStatus: {Rate: 1}}})
+	assert.Equal(t, nil, l.Wait(context.Background(), OperationOrderStatus, ""))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, l.Wait(ctx, OperationOrderStatus, ""))
+	assert.Equal(t, int64(1), l.Stats()[OperationOrderStatus][PriorityInteractive].Requests)
+}
+
+func TestRateLimiter_WaitPriority(t *testing.T) {
+	l := NewRateLimiter(RateLimiterConfig{Operations: map[Operation]RateLimit{OperationDeposit: {Rate: 20, Burst: 1}}})
+	assert.Equal(t, nil, l.Wait(context.Background(), OperationDeposit, ""))
+
+	var mu sync.Mutex
+	var order []Priority
+	var wg sync.WaitGroup
+	wait := func(p Priority) {
+		defer wg.Done()
+		assert.Equal(t, nil, l.Wait(WithPriority(context.Background(), p), OperationDeposit, ""))
+		mu.Lock()
+		order = append(order, p)
+		mu.Unlock()
+	}
+
+	wg.Add(2)
+	go wait(PriorityBatch)
+	time.Sleep(10 * time.Millisecond)
+	go wait(PriorityInteractive)
+	wg.Wait()
+
+	assert.Equal(t, []Priority{PriorityInteractive, PriorityBatch}, order)
+	assert.Equal(t, int64(1), l.Stats()[OperationDeposit][PriorityBatch].Requests)
+}
+
+func TestSDK_RateLimiter(t *testing.T) {
+	client := &ClientMockByOrder{statuses: map[string][]OrderStatusCode{"1": {StatusApproved}}, calls: map[string]int{}}
+	sdk := testPollSDK(client)
+	sdk.RateLimiter = NewRateLimiter(RateLimiterConfig{Operations: map[Operation]RateLimit{OperationOrderStatus: {Rate: 100}}})
+
+	_, err := sdk.OrderStatus(OrderStatus{MerchantOrderID: "m-1", OrderID: "1"})
+	assert.Equal(t, nil, err)
+
+	refs := []OrderRef{{MerchantOrderID: "m-1", OrderID: "1"}, {MerchantOrderID: "m-1", OrderID: "1"}}
+	_, summary := sdk.OrderStatusBatch(context.Background(), refs, BatchOptions{RateLimit: 1000})
+	assert.Equal(t, 2, summary.ByStatus[StatusApproved])
+
+	stats := sdk.RateLimiter.Stats()[OperationOrderStatus]
+	assert.Equal(t, int64(1), stats[PriorityInteractive].Requests)
+	assert.Equal(t, int64(2), stats[PriorityBatch].Requests)
+
+	//a cancelled wait does not send the request
+	sdk.RateLimiter = NewRateLimiter(RateLimiterConfig{Operations: map[Operation]RateLimit{OperationOrderStatus: {Rate: 0.001}}})
+	_, err = sdk.OrderStatus(OrderStatus{MerchantOrderID: "m-1", OrderID: "1"})
+	assert.Equal(t, nil, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = sdk.OrderStatusContext(ctx, OrderStatus{MerchantOrderID: "m-1", OrderID: "1"})
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, 4, client.calls["1"])
+}
@@ -0,0 +1,163 @@
+package reconcile
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/zota/go-sdk/zota"
+)
+
+// LocalOrder is an order of the merchant records
+// an empty Status is not compared
+type LocalOrder struct {
+	MerchantOrderID string               `json:"merchantOrderID"`
+	Amount          zota.Money           `json:"amount"`
+	Currency        string               `json:"currency"`
+	Status          zota.OrderStatusCode `json:"status"`
+}
+
+// LocalSource iterates over the merchant records, as zota.RowIterator does over the report rows
+type LocalSource interface {
+	Next() bool
+	Order() LocalOrder
+	Err() error
+}
+
+// Orders returns a LocalSource iterating over orders
+func Orders(orders []LocalOrder) LocalSource {
+	return &sliceSource{orders: orders, next: -1}
+}
+
+// sliceSource is a LocalSource over a slice
+type sliceSource struct {
+	orders []LocalOrder
+	next   int
+}
+
+// Next implements LocalSource
+func (s *sliceSource) Next() bool {
+	if s.next+1 >= len(s.orders) {
+		return false
+	}
+	s.next++
+	return true
+}
+
+// Order implements LocalSource
+func (s *sliceSource) Order() LocalOrder {
+	return s.orders[s.next]
+}
+
+// Err implements LocalSource
+func (s *sliceSource) Err() error {
+	return nil
+}
+
+// csvColumns are the required columns of a local csv
+var csvColumns = []string{"merchantOrderID", "amount", "currency", "status"}
+
+// CSVSource is a LocalSource reading the orders from a csv with a header
+// the columns merchantOrderID, amount, currency and status are required,
+// their names are matched ignoring case and underscores, e.g. merchant_order_id
+// other columns are ignored
+type CSVSource struct {
+	csv     *csv.Reader
+	columns []int
+	order   LocalOrder
+	err     error
+}
+
+// NewCSVSource creates a CSVSource reading r
+func NewCSVSource(r io.Reader) *CSVSource {
+	c := csv.NewReader(r)
+	c.FieldsPerRecord = -1
+	return &CSVSource{csv: c}
+}
+
+// Next implements LocalSource
+func (c *CSVSource) Next() bool {
+	if c.err != nil {
+		return false
+	}
+	if c.columns == nil && !c.readHeader() {
+		return false
+	}
+
+	record, err := c.csv.Read()
+	if err == io.EOF {
+		return false
+	}
+	if err != nil {
+		c.err = fmt.Errorf("unexpected local csv:%v", err)
+		return false
+	}
+	line, _ := c.csv.FieldPos(0)
+
+	value := func(i int) string {
+		if c.columns[i] >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[c.columns[i]])
+	}
+	amount, err := zota.ParseMoney(value(1))
+	if err != nil {
+		c.err = fmt.Errorf("local csv line %v: %v", line, err)
+		return false
+	}
+	c.order = LocalOrder{
+		MerchantOrderID: value(0),
+		Amount:          amount,
+		Currency:        strings.ToUpper(value(2)),
+		Status:          zota.OrderStatusCode(strings.ToUpper(value(3))),
+	}
+	if c.order.MerchantOrderID == "" {
+		c.err = fmt.Errorf("local csv line %v: merchantOrderID is required", line)
+		return false
+	}
+	return true
+}
+
+// readHeader maps the required columns to the header
+func (c *CSVSource) readHeader() bool {
+	header, err := c.csv.Read()
+	if err == io.EOF {
+		return false
+	}
+	if err != nil {
+		c.err = fmt.Errorf("unexpected local csv:%v", err)
+		return false
+	}
+
+	index := map[string]int{}
+	for i, name := range header {
+		index[columnKey(name)] = i
+	}
+	columns := make([]int, len(csvColumns))
+	for i, name := range csvColumns {
+		column, ok := index[columnKey(name)]
+		if !ok {
+			c.err = fmt.Errorf("local csv has no %v column", name)
+			return false
+		}
+		columns[i] = column
+	}
+	c.columns = columns
+	return true
+}
+
+// columnKey normalizes a column name, ignoring case and underscores
+func columnKey(name string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(name), "_", ""))
+}
+
+// Order implements LocalSource
+func (c *CSVSource) Order() LocalOrder {
+	return c.order
+}
+
+// Err implements LocalSource
+func (c *CSVSource) Err() error {
+	return c.err
+}
@@ -0,0 +1,54 @@
+package reconcile
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zota/go-sdk/zota"
+)
+
+func TestCSVSource(t *testing.T) {
+	csv := "Merchant_Order_ID,booked_at,Amount,currency,status\nm-1,2020-08-05,500.00,myr,approved\nm-2,2020-08-05,1.5,USD,\n"
+	source := NewCSVSource(strings.NewReader(csv))
+
+	var orders []LocalOrder
+	for source.Next() {
+		orders = append(orders, source.Order())
+	}
+	assert.Equal(t, nil, source.Err())
+	assert.Equal(t, []LocalOrder{
+		{MerchantOrderID: "m-1", Amount: zota.MustParseMoney("500"), Currency: "MYR", Status: zota.StatusApproved},
+		{MerchantOrderID: "m-2", Amount: zota.MustParseMoney("1.5"), Currency: "USD"},
+	}, orders)
+}
+
+func TestCSVSourceErrors(t *testing.T) {
+	tests := map[string]error{
+		"merchantOrderID,amount,currency\n":                            fmt.Errorf("local csv has no status column"),
+		"merchantOrderID,amount,currency,status\nm-1,x,USD,APPROVED\n": fmt.Errorf("local csv line 2: invalid amount %q", "x"),
+		"merchantOrderID,amount,currency,status\n,1,USD,APPROVED\n":    fmt.Errorf("local csv line 2: merchantOrderID is required"),
+		"": nil,
+	}
+	for csv, expected := range tests {
+		source := NewCSVSource(strings.NewReader(csv))
+		for source.Next() {
+		}
+		assert.Equal(t, expected, source.Err(), csv)
+	}
+
+	source := NewCSVSource(strings.NewReader("merchantOrderID,amount,currency,status\n\"m-1,1\n"))
+	assert.False(t, source.Next())
+	assert.True(t, strings.HasPrefix(source.Err().Error(), "unexpected local csv:"))
+}
+
+func TestOrders(t *testing.T) {
+	source := Orders([]LocalOrder{{MerchantOrderID: "m-1"}, {MerchantOrderID: "m-2"}})
+	assert.True(t, source.Next())
+	assert.Equal(t, "m-1", source.Order().MerchantOrderID)
+	assert.True(t, source.Next())
+	assert.Equal(t, "m-2", source.Order().MerchantOrderID)
+	assert.False(t, source.Next())
+	assert.Equal(t, nil, source.Err())
+}
@@ -0,0 +1,134 @@
+package reconcile
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/zota/go-sdk/zota"
+)
+
+// CurrencySummary summarizes the entries of a currency
+// the totals are the amounts of the orders of each side, Difference is LocalTotal - ZotaTotal
+type CurrencySummary struct {
+	Currency   string       `json:"currency"`
+	Orders     int          `json:"orders"`
+	Counts     map[Kind]int `json:"counts"`
+	LocalTotal zota.Money   `json:"localTotal"`
+	ZotaTotal  zota.Money   `json:"zotaTotal"`
+	Difference zota.Money   `json:"difference"`
+}
+
+// summarize returns the summaries per currency of the entries, sorted by currency
+// the amounts of an order with a different currency at Zota are added to the totals of each currency
+func summarize(entries []Entry) []CurrencySummary {
+	byCurrency := map[string]*CurrencySummary{}
+	get := func(currency string) *CurrencySummary {
+		s, ok := byCurrency[currency]
+		if !ok {
+			s = &CurrencySummary{Currency: currency, Counts: map[Kind]int{}}
+			byCurrency[currency] = s
+		}
+		return s
+	}
+
+	for _, e := range entries {
+		s := get(e.Currency)
+		s.Orders++
+		s.Counts[e.Kind]++
+		if e.Kind != MissingLocally {
+			s.LocalTotal = s.LocalTotal.Add(e.LocalAmount)
+		}
+		if e.Kind != MissingAtZota {
+			z := get(e.ZotaCurrency)
+			z.ZotaTotal = z.ZotaTotal.Add(e.ZotaAmount)
+		}
+	}
+
+	summary := make([]CurrencySummary, 0, len(byCurrency))
+	for _, s := range byCurrency {
+		s.Difference = s.LocalTotal.Sub(s.ZotaTotal)
+		summary = append(summary, *s)
+	}
+	sort.Slice(summary, func(i, j int) bool { return summary[i].Currency < summary[j].Currency })
+	return summary
+}
+
+// WriteJSON writes the entries which are not matched and the summary as a json object
+//
+//	{"diff":[...],"summary":[...]}
+func (r *Result) WriteJSON(w io.Writer) error {
+	diff := r.Diff()
+	if diff == nil {
+		diff = []Entry{}
+	}
+	return json.NewEncoder(w).Encode(struct {
+		Diff    []Entry           `json:"diff"`
+		Summary []CurrencySummary `json:"summary"`
+	}{diff, r.Summary})
+}
+
+// csvHeader is the header of WriteCSV
+var csvHeader = []string{
+	"merchant_order_id", "kind", "currency", "local_amount", "local_status",
+	"order_id", "zota_currency", "zota_amount", "original_amount", "amount_changed", "zota_status", "note",
+}
+
+// WriteCSV writes the entries which are not matched as csv
+func (r *Result) WriteCSV(w io.Writer) error {
+	c := csv.NewWriter(w)
+	err := c.Write(csvHeader)
+	if err != nil {
+		return err
+	}
+	for _, e := range r.Diff() {
+		local, zotaAmount, original := "", "", ""
+		if e.Kind != MissingLocally {
+			local = e.LocalAmount.String()
+		}
+		if e.Kind != MissingAtZota {
+			zotaAmount = e.ZotaAmount.String()
+			original = e.OriginalAmount.String()
+		}
+		orderID := ""
+		if e.OrderID != 0 {
+			orderID = strconv.FormatInt(e.OrderID, 10)
+		}
+		err = c.Write([]string{
+			e.MerchantOrderID, string(e.Kind), e.Currency, local, string(e.LocalStatus),
+			orderID, e.ZotaCurrency, zotaAmount, original, strconv.FormatBool(e.AmountChanged), string(e.ZotaStatus), e.Note,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	c.Flush()
+	return c.Error()
+}
+
+// WriteSummary writes the summary per currency as a text table, amounts with 2 decimal places
+func (r *Result) WriteSummary(w io.Writer) error {
+	t := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	header := []string{"CURRENCY", "ORDERS"}
+	for _, kind := range Kinds {
+		header = append(header, strings.ToUpper(strings.ReplaceAll(string(kind), "_", " ")))
+	}
+	header = append(header, "LOCAL TOTAL", "ZOTA TOTAL", "DIFFERENCE")
+	fmt.Fprintln(t, strings.Join(header, "\t"))
+
+	for _, s := range r.Summary {
+		line := []string{s.Currency, strconv.Itoa(s.Orders)}
+		for _, kind := range Kinds {
+			line = append(line, strconv.Itoa(s.Counts[kind]))
+		}
+		line = append(line, s.LocalTotal.Format(2), s.ZotaTotal.Format(2), s.Difference.Format(2))
+		fmt.Fprintln(t, strings.Join(line, "\t"))
+	}
+	return t.Flush()
+}
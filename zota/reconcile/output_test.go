@@ -0,0 +1,92 @@
+package reconcile
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zota/go-sdk/zota"
+)
+
+func TestResult_Summary(t *testing.T) {
+	res, err := Reconcile(Orders(testOrders), testRows(), Options{})
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, []CurrencySummary{
+		{
+			Currency:   "MYR",
+			Orders:     4,
+			Counts:     map[Kind]int{Matched: 2, AmountMismatch: 2},
+			LocalTotal: zota.MustParseMoney("680"),
+			ZotaTotal:  zota.MustParseMoney("660"),
+			Difference: zota.MustParseMoney("20"),
+		},
+		{
+			Currency:   "THB",
+			Counts:     map[Kind]int{},
+			ZotaTotal:  zota.MustParseMoney("10"),
+			Difference: zota.MustParseMoney("-10"),
+		},
+		{
+			Currency:   "USD",
+			Orders:     4,
+			Counts:     map[Kind]int{StatusMismatch: 1, MissingAtZota: 1, MissingLocally: 1, AmountMismatch: 1},
+			LocalTotal: zota.MustParseMoney("36"),
+			ZotaTotal:  zota.MustParseMoney("60"),
+			Difference: zota.MustParseMoney("-24"),
+		},
+	}, res.Summary)
+
+	var buf bytes.Buffer
+	assert.Equal(t, nil, res.WriteSummary(&buf))
+	assert.Equal(t, ""+
+		"CURRENCY  ORDERS  MATCHED  AMOUNT MISMATCH  STATUS MISMATCH  MISSING AT ZOTA  MISSING LOCALLY  LOCAL TOTAL  ZOTA TOTAL  DIFFERENCE\n"+
+		"MYR       4       2        2                0                0                0                680.00       660.00      20.00\n"+
+		"THB       0       0        0                0                0                0                0.00         10.00       -10.00\n"+
+		"USD       4       0        1                1                1                1                36.00        60.00       -24.00\n",
+		buf.String())
+}
+
+func TestResult_WriteCSV(t *testing.T) {
+	res, err := Reconcile(Orders(testOrders), testRows(), Options{})
+	assert.Equal(t, nil, err)
+
+	var buf bytes.Buffer
+	assert.Equal(t, nil, res.WriteCSV(&buf))
+	assert.Equal(t, ""+
+		"merchant_order_id,kind,currency,local_amount,local_status,order_id,zota_currency,zota_amount,original_amount,amount_changed,zota_status,note\n"+
+		"m-2,amount_mismatch,MYR,100.00000000,APPROVED,2,MYR,90.00000000,100.00000000,true,APPROVED,amount changed by Zota from 100.00000000 to 90.00000000\n"+
+		"m-3,status_mismatch,USD,20.00000000,APPROVED,3,USD,20.00000000,20.00000000,false,DECLINED,\n"+
+		"m-4,amount_mismatch,MYR,10.00000000,,4,THB,10.00000000,10.00000000,false,APPROVED,currency THB at Zota\n"+
+		"m-5,missing_at_zota,USD,5.00000000,APPROVED,,,,,false,,\n"+
+		"m-6,missing_locally,USD,,,5,USD,30.00000000,30.00000000,false,DECLINED,\n"+
+		"m-8,amount_mismatch,USD,11.00000000,DECLINED,9,USD,10.00000000,10.00000000,false,APPROVED,\n",
+		buf.String())
+}
+
+func TestResult_WriteJSON(t *testing.T) {
+	res, err := Reconcile(Orders(testOrders[:1]), testRows(), Options{})
+	assert.Equal(t, nil, err)
+
+	var buf bytes.Buffer
+	assert.Equal(t, nil, res.WriteJSON(&buf))
+
+	var out struct {
+		Diff    []Entry           `json:"diff"`
+		Summary []CurrencySummary `json:"summary"`
+	}
+	assert.Equal(t, nil, json.Unmarshal(buf.Bytes(), &out))
+	assert.Equal(t, res.Diff(), out.Diff)
+	assert.Equal(t, res.Summary, out.Summary)
+	assert.Contains(t, buf.String(), `"kind":"missing_locally"`)
+	assert.Contains(t, buf.String(), `"counts":{"matched":1,"missing_locally":2}`)
+
+	res, err = Reconcile(Orders(nil), zota.NewReportStream(io.NopCloser(strings.NewReader(""))), Options{})
+	assert.Equal(t, nil, err)
+	buf.Reset()
+	assert.Equal(t, nil, res.WriteJSON(&buf))
+	assert.Equal(t, "{\"diff\":[],\"summary\":[]}\n", buf.String())
+}
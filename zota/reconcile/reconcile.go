@@ -0,0 +1,183 @@
+// Package reconcile compares the merchant order records with the Zota orders report
+// the orders are matched by merchant order ID and classified as matched,
+// amount mismatch, status mismatch, missing at Zota or missing locally
+package reconcile
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zota/go-sdk/zota"
+)
+
+// Kind is the classification of a reconciled order
+type Kind string
+
+const (
+	// Matched orders have the same amount, currency and status on both sides
+	Matched Kind = "matched"
+	// AmountMismatch orders have a different amount or currency
+	AmountMismatch Kind = "amount_mismatch"
+	// StatusMismatch orders have the same amount but a different status
+	StatusMismatch Kind = "status_mismatch"
+	// MissingAtZota orders are only in the merchant records
+	MissingAtZota Kind = "missing_at_zota"
+	// MissingLocally orders are only in the Zota report
+	MissingLocally Kind = "missing_locally"
+)
+
+// Kinds are all the kinds, in the order of the summaries
+var Kinds = []Kind{Matched, AmountMismatch, StatusMismatch, MissingAtZota, MissingLocally}
+
+// Entry is the reconciliation of an order
+// the local fields are empty for the orders missing locally, the Zota fields for the orders missing at Zota
+type Entry struct {
+	MerchantOrderID string `json:"merchantOrderID"`
+	Kind            Kind   `json:"kind"`
+	Currency        string `json:"currency"`
+	Note            string `json:"note,omitempty"`
+
+	LocalAmount zota.Money           `json:"localAmount"`
+	LocalStatus zota.OrderStatusCode `json:"localStatus,omitempty"`
+
+	OrderID        int64                `json:"orderID,omitempty"`
+	ZotaCurrency   string               `json:"zotaCurrency,omitempty"`
+	ZotaAmount     zota.Money           `json:"zotaAmount"`
+	OriginalAmount zota.Money           `json:"originalAmount"`
+	AmountChanged  bool                 `json:"amountChanged"`
+	ZotaStatus     zota.OrderStatusCode `json:"zotaStatus,omitempty"`
+}
+
+// Options configures Reconcile
+type Options struct {
+	// AcceptOriginalAmount matches a local amount equal to the original_amount of an order
+	// whose amount was changed by Zota, it is an amount mismatch by default
+	AcceptOriginalAmount bool
+}
+
+// Result is the outcome of Reconcile
+type Result struct {
+	// Entries are sorted by merchant order ID
+	Entries []Entry
+	// Summary has a summary per currency, sorted by currency
+	Summary []CurrencySummary
+}
+
+// Reconcile matches the local orders with the rows of the Zota report by merchant order ID
+// the local merchant order IDs must be unique,
+// for a merchant order ID with several Zota orders the approved one, else the latest created, is compared
+func Reconcile(local LocalSource, report zota.RowIterator, opts Options) (*Result, error) {
+	orders := map[string]LocalOrder{}
+	for local.Next() {
+		o := local.Order()
+		if _, ok := orders[o.MerchantOrderID]; ok {
+			return nil, fmt.Errorf("duplicate local merchantOrderID %v", o.MerchantOrderID)
+		}
+		orders[o.MerchantOrderID] = o
+	}
+	if local.Err() != nil {
+		return nil, local.Err()
+	}
+
+	rows := map[string]zota.ReportRow{}
+	for report.Next() {
+		row := report.Row()
+		current, ok := rows[row.MerchantOrderID]
+		if !ok || preferRow(row, current) {
+			rows[row.MerchantOrderID] = row
+		}
+	}
+	if report.Err() != nil {
+		return nil, report.Err()
+	}
+
+	entries := make([]Entry, 0, len(orders)+len(rows))
+	for id, o := range orders {
+		row, ok := rows[id]
+		if !ok {
+			entries = append(entries, Entry{
+				MerchantOrderID: id,
+				Kind:            MissingAtZota,
+				Currency:        o.Currency,
+				LocalAmount:     o.Amount,
+				LocalStatus:     o.Status,
+			})
+			continue
+		}
+		entries = append(entries, compare(o, row, opts))
+	}
+	for id, row := range rows {
+		if _, ok := orders[id]; !ok {
+			e := zotaEntry(row)
+			e.Kind = MissingLocally
+			e.Currency = row.OrderCurrency
+			entries = append(entries, e)
+		}
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].MerchantOrderID < entries[j].MerchantOrderID })
+	return &Result{Entries: entries, Summary: summarize(entries)}, nil
+}
+
+// Diff returns the entries which are not matched
+func (r *Result) Diff() []Entry {
+	var diff []Entry
+	for _, e := range r.Entries {
+		if e.Kind != Matched {
+			diff = append(diff, e)
+		}
+	}
+	return diff
+}
+
+// compare classifies an order present on both sides
+func compare(o LocalOrder, row zota.ReportRow, opts Options) Entry {
+	e := zotaEntry(row)
+	e.Currency = o.Currency
+	e.LocalAmount = o.Amount
+	e.LocalStatus = o.Status
+
+	switch {
+	case !strings.EqualFold(o.Currency, row.OrderCurrency):
+		e.Kind = AmountMismatch
+		e.Note = fmt.Sprintf("currency %v at Zota", row.OrderCurrency)
+	case o.Amount.Cmp(row.OrderAmount) == 0:
+		e.Kind = Matched
+	case row.AmountChanged && o.Amount.Cmp(row.OriginalAmount) == 0:
+		e.Note = fmt.Sprintf("amount changed by Zota from %v to %v", row.OriginalAmount, row.OrderAmount)
+		e.Kind = AmountMismatch
+		if opts.AcceptOriginalAmount {
+			e.Kind = Matched
+		}
+	default:
+		e.Kind = AmountMismatch
+	}
+
+	if e.Kind == Matched && o.Status != "" && o.Status != row.Status {
+		e.Kind = StatusMismatch
+	}
+	return e
+}
+
+// zotaEntry returns an entry with the Zota fields of the row
+func zotaEntry(row zota.ReportRow) Entry {
+	return Entry{
+		MerchantOrderID: row.MerchantOrderID,
+		OrderID:         row.ID,
+		ZotaCurrency:    row.OrderCurrency,
+		ZotaAmount:      row.OrderAmount,
+		OriginalAmount:  row.OriginalAmount,
+		AmountChanged:   row.AmountChanged,
+		ZotaStatus:      row.Status,
+	}
+}
+
+// preferRow reports whether row replaces current for the same merchant order ID
+// an approved order is preferred, then the latest created
+func preferRow(row zota.ReportRow, current zota.ReportRow) bool {
+	if row.Status.IsSuccess() != current.Status.IsSuccess() {
+		return row.Status.IsSuccess()
+	}
+	return row.CreatedAt.After(current.CreatedAt)
+}
@@ -0,0 +1,102 @@
+package reconcile
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zota/go-sdk/zota"
+)
+
+// testReport is a Zota orders report
+const testReport = `id,merchant_order_id,order_currency,order_amount,original_amount,amount_changed,status,created_at
+1,m-1,MYR,500.00000000,500.00000000,false,APPROVED,2020-08-05 13:00:00 +0000 UTC
+2,m-2,MYR,90.00000000,100.00000000,true,APPROVED,2020-08-05 13:00:00 +0000 UTC
+3,m-3,USD,20.00000000,20.00000000,false,DECLINED,2020-08-05 13:00:00 +0000 UTC
+4,m-4,THB,10.00000000,10.00000000,false,APPROVED,2020-08-05 13:00:00 +0000 UTC
+5,m-6,USD,30.00000000,30.00000000,false,DECLINED,2020-08-05 13:00:00 +0000 UTC
+6,m-7,MYR,70.00000000,70.00000000,false,DECLINED,2020-08-05 12:00:00 +0000 UTC
+7,m-7,MYR,70.00000000,70.00000000,false,APPROVED,2020-08-05 13:00:00 +0000 UTC
+8,m-7,MYR,70.00000000,70.00000000,false,DECLINED,2020-08-05 14:00:00 +0000 UTC
+9,m-8,USD,10.00000000,10.00000000,false,APPROVED,2020-08-05 13:00:00 +0000 UTC
+`
+
+// testOrders are the local orders of testReport
+var testOrders = []LocalOrder{
+	{MerchantOrderID: "m-1", Amount: zota.MustParseMoney("500"), Currency: "MYR", Status: zota.StatusApproved},
+	{MerchantOrderID: "m-2", Amount: zota.MustParseMoney("100"), Currency: "MYR", Status: zota.StatusApproved},
+	{MerchantOrderID: "m-3", Amount: zota.MustParseMoney("20"), Currency: "USD", Status: zota.StatusApproved},
+	{MerchantOrderID: "m-4", Amount: zota.MustParseMoney("10"), Currency: "MYR"},
+	{MerchantOrderID: "m-5", Amount: zota.MustParseMoney("5"), Currency: "USD", Status: zota.StatusApproved},
+	{MerchantOrderID: "m-7", Amount: zota.MustParseMoney("70"), Currency: "MYR", Status: zota.StatusApproved},
+	{MerchantOrderID: "m-8", Amount: zota.MustParseMoney("11"), Currency: "USD", Status: zota.StatusDeclined},
+}
+
+func testRows() zota.RowIterator {
+	return zota.NewReportStream(io.NopCloser(strings.NewReader(testReport)))
+}
+
+func TestReconcile(t *testing.T) {
+	res, err := Reconcile(Orders(testOrders), testRows(), Options{})
+	assert.Equal(t, nil, err)
+
+	kinds := map[string]Kind{}
+	for _, e := range res.Entries {
+		kinds[e.MerchantOrderID] = e.Kind
+	}
+	assert.Equal(t, map[string]Kind{
+		"m-1": Matched,
+		"m-2": AmountMismatch,
+		"m-3": StatusMismatch,
+		"m-4": AmountMismatch,
+		"m-5": MissingAtZota,
+		"m-6": MissingLocally,
+		"m-7": Matched,
+		"m-8": AmountMismatch,
+	}, kinds)
+
+	assert.Equal(t, Entry{
+		MerchantOrderID: "m-2",
+		Kind:            AmountMismatch,
+		Currency:        "MYR",
+		Note:            "amount changed by Zota from 100.00000000 to 90.00000000",
+		LocalAmount:     zota.MustParseMoney("100"),
+		LocalStatus:     zota.StatusApproved,
+		OrderID:         2,
+		ZotaCurrency:    "MYR",
+		ZotaAmount:      zota.MustParseMoney("90"),
+		OriginalAmount:  zota.MustParseMoney("100"),
+		AmountChanged:   true,
+		ZotaStatus:      zota.StatusApproved,
+	}, res.Entries[1])
+	assert.Equal(t, "currency THB at Zota", res.Entries[3].Note)
+	assert.Equal(t, Entry{MerchantOrderID: "m-5", Kind: MissingAtZota, Currency: "USD", LocalAmount: zota.MustParseMoney("5"), LocalStatus: zota.StatusApproved}, res.Entries[4])
+	assert.Equal(t, "USD", res.Entries[5].Currency)
+
+	//the approved order is compared
+	assert.Equal(t, int64(7), res.Entries[6].OrderID)
+
+	assert.Equal(t, 6, len(res.Diff()))
+}
+
+func TestReconcileAcceptOriginalAmount(t *testing.T) {
+	res, err := Reconcile(Orders(testOrders[1:2]), testRows(), Options{AcceptOriginalAmount: true})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "m-2", res.Entries[1].MerchantOrderID)
+	assert.Equal(t, Matched, res.Entries[1].Kind)
+	assert.Equal(t, "amount changed by Zota from 100.00000000 to 90.00000000", res.Entries[1].Note)
+}
+
+func TestReconcileErrors(t *testing.T) {
+	_, err := Reconcile(Orders([]LocalOrder{{MerchantOrderID: "m-1"}, {MerchantOrderID: "m-1"}}), testRows(), Options{})
+	assert.Equal(t, fmt.Errorf("duplicate local merchantOrderID m-1"), err)
+
+	_, err = Reconcile(NewCSVSource(strings.NewReader("id\n")), testRows(), Options{})
+	assert.Equal(t, fmt.Errorf("local csv has no merchantOrderID column"), err)
+
+	report := zota.NewReportStream(io.NopCloser(strings.NewReader("id,order_amount\n1,x\n")))
+	_, err = Reconcile(Orders(nil), report, Options{})
+	assert.Equal(t, fmt.Errorf("orders report line 2: column order_amount: invalid amount %q", "x"), err)
+}
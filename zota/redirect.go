@@ -12,7 +12,7 @@ type redirect struct {
 	ErrorMessage      string
 	MerchantOrderID   string
 	OrderID           string
-	Status            string
+	Status            OrderStatusCode
 }
 
 // Redirect parse the redirect query params into redirect struct
@@ -24,7 +24,7 @@ func (s *SDK) Redirect(url url.URL) (r redirect, err error) {
 	r.ErrorMessage = url.Query().Get("errorMessage")
 	r.BillingDescriptor = url.Query().Get("billingDescriptor")
 	r.Signature = url.Query().Get("signature")
-	r.Status = url.Query().Get("status")
+	r.Status = OrderStatusCode(url.Query().Get("status"))
 
 	isValid := s.validateredirectSign(&r)
 	if isValid != true {
@@ -37,6 +37,6 @@ func (s *SDK) Redirect(url url.URL) (r redirect, err error) {
 // validateredirectSign generate and validate
 // redirect sign
 func (s *SDK) validateredirectSign(c *redirect) bool {
-	signature := s.sign(c.Status, c.OrderID, c.MerchantOrderID)
+	signature := s.sign(string(c.Status), c.OrderID, c.MerchantOrderID)
 	return signature == c.Signature
 }
@@ -0,0 +1,191 @@
+package zota
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"sync"
+)
+
+// Merchant represents a merchant configuration of the Registry
+// Endpoints are the EndpointIDs of the merchant in addition to
+// SDK.EndpointID and the endpoints of an EndpointTable router
+type Merchant struct {
+	Name      string
+	SDK       *SDK
+	Endpoints []string
+}
+
+// endpoints returns all the EndpointIDs of the merchant
+func (m Merchant) endpoints() []string {
+	var endpoints []string
+	add := func(e string) {
+		if e != "" && !containsFold(endpoints, e) {
+			endpoints = append(endpoints, e)
+		}
+	}
+	add(m.SDK.EndpointID)
+	if lister, ok := m.SDK.EndpointRouter.(interface{ Endpoints() []string }); ok {
+		for _, e := range lister.Endpoints() {
+			add(e)
+		}
+	}
+	for _, e := range m.Endpoints {
+		add(e)
+	}
+	return endpoints
+}
+
+// Registry holds the configurations of several merchants
+// and verifies callbacks and redirects with the credentials
+// of the merchant owning the endpoint
+// it is safe for concurrent use
+type Registry struct {
+	mu        sync.RWMutex
+	merchants map[string]Merchant
+	endpoints map[string]string
+}
+
+// NewRegistry creates an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{
+		merchants: map[string]Merchant{},
+		endpoints: map[string]string{},
+	}
+}
+
+// Add adds a merchant to the registry
+// returns an error if the name is already used
+// or if an endpoint belongs to another merchant
+func (r *Registry) Add(m Merchant) error {
+	if m.Name == "" {
+		return fmt.Errorf("Name is required")
+	}
+	if m.SDK == nil {
+		return fmt.Errorf("SDK is required")
+	}
+	endpoints := m.endpoints()
+	if len(endpoints) == 0 {
+		return fmt.Errorf("merchant %v has no endpoints", m.Name)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.merchants[m.Name]; ok {
+		return fmt.Errorf("merchant %v already exists", m.Name)
+	}
+	for _, e := range endpoints {
+		if owner, ok := r.endpoints[e]; ok {
+			return fmt.Errorf("endpointID %v already belongs to merchant %v", e, owner)
+		}
+	}
+
+	r.merchants[m.Name] = m
+	for _, e := range endpoints {
+		r.endpoints[e] = m.Name
+	}
+	return nil
+}
+
+// Remove removes the merchant and its endpoints from the registry
+func (r *Registry) Remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.merchants, name)
+	for e, owner := range r.endpoints {
+		if owner == name {
+			delete(r.endpoints, e)
+		}
+	}
+}
+
+// Get returns the merchant by name
+func (r *Registry) Get(name string) (Merchant, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	m, ok := r.merchants[name]
+	return m, ok
+}
+
+// ByEndpoint returns the merchant owning the EndpointID
+func (r *Registry) ByEndpoint(endpointID string) (Merchant, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	name, ok := r.endpoints[endpointID]
+	if !ok {
+		return Merchant{}, false
+	}
+	return r.merchants[name], true
+}
+
+// Names returns the names of all merchants in order
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.merchants))
+	for name := range r.merchants {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Callback selects the merchant by the endpointID of the callback data
+// and parses the callback with the merchant credentials
+// the endpoint is accepted as owned by the merchant, including Merchant.Endpoints
+func (r *Registry) Callback(b []byte) (Merchant, callback, error) {
+	var payload struct {
+		EndpointID string `json:"endpointID"`
+	}
+	err := json.Unmarshal(b, &payload)
+	if err != nil {
+		return Merchant{}, callback{}, fmt.Errorf("unexpected callback json:%v", err)
+	}
+
+	m, ok := r.ByEndpoint(payload.EndpointID)
+	if !ok {
+		return Merchant{}, callback{}, fmt.Errorf("unknown endpointID %v", payload.EndpointID)
+	}
+
+	c, err := m.SDK.parseCallback(b)
+	if err != nil {
+		return Merchant{}, callback{}, err
+	}
+	return m, c, nil
+}
+
+// Redirect parses the redirect query params with the credentials of the merchant
+// redirects have no endpointID, if the RedirectURL of the order contains
+// an endpointID query param it is used to select the merchant,
+// otherwise the signature is checked against every merchant
+func (r *Registry) Redirect(u url.URL) (Merchant, redirect, error) {
+	if endpointID := u.Query().Get("endpointID"); endpointID != "" {
+		m, ok := r.ByEndpoint(endpointID)
+		if !ok {
+			return Merchant{}, redirect{}, fmt.Errorf("unknown endpointID %v", endpointID)
+		}
+		res, err := m.SDK.Redirect(u)
+		if err != nil {
+			return Merchant{}, redirect{}, err
+		}
+		return m, res, nil
+	}
+
+	for _, name := range r.Names() {
+		m, ok := r.Get(name)
+		if !ok {
+			continue
+		}
+		res, err := m.SDK.Redirect(u)
+		if err == nil {
+			return m, res, nil
+		}
+	}
+	return Merchant{}, redirect{}, fmt.Errorf("wrong signature")
+}
@@ -0,0 +1,151 @@
+package zota
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testRegistry returns a Registry with two merchants
+func testRegistry(t *testing.T) *Registry {
+	r := NewRegistry()
+	assert.Equal(t, nil, r.Add(Merchant{
+		Name: "brand-a",
+		SDK: &SDK{
+			MerchantID:        "MERCHANT_A",
+			MerchantSecretKey: "SECRET_A",
+			EndpointID:        "100001",
+			ApiBaseURL:        SANDBOX,
+			EndpointRouter:    EndpointTable{{Currency: "THB", EndpointID: "100002"}},
+		},
+	}))
+	assert.Equal(t, nil, r.Add(Merchant{
+		Name: "brand-b",
+		SDK: &SDK{
+			MerchantID:        "MERCHANT_B",
+			MerchantSecretKey: "SECRET_B",
+			EndpointID:        "200001",
+			ApiBaseURL:        SANDBOX,
+		},
+		Endpoints: []string{"200002"},
+	}))
+	return r
+}
+
+// signedCallback returns callback data for the endpoint signed with the SDK secret
+func signedCallback(s *SDK, endpointID string) []byte {
+	sign := s.sign(endpointID + "1" + "2" + "APPROVED" + "10.00" + "a@b.c")
+	return []byte(fmt.Sprintf(`{"endpointID":"%v","orderID":"1","merchantOrderID":"2","status":"APPROVED","amount":"10.00","customerEmail":"a@b.c","signature":"%v"}`, endpointID, sign))
+}
+
+func TestRegistry_Add(t *testing.T) {
+	r := testRegistry(t)
+	assert.Equal(t, []string{"brand-a", "brand-b"}, r.Names())
+
+	err := r.Add(Merchant{Name: "brand-a", SDK: &SDK{EndpointID: "300001"}})
+	assert.Equal(t, fmt.Errorf("merchant brand-a already exists"), err)
+
+	err = r.Add(Merchant{Name: "brand-c", SDK: &SDK{EndpointID: "100002"}})
+	assert.Equal(t, fmt.Errorf("endpointID 100002 already belongs to merchant brand-a"), err)
+
+	err = r.Add(Merchant{Name: "brand-c", SDK: &SDK{}})
+	assert.Equal(t, fmt.Errorf("merchant brand-c has no endpoints"), err)
+
+	err = r.Add(Merchant{Name: "brand-c"})
+	assert.Equal(t, fmt.Errorf("SDK is required"), err)
+
+	m, ok := r.ByEndpoint("200002")
+	assert.True(t, ok)
+	assert.Equal(t, "brand-b", m.Name)
+
+	r.Remove("brand-b")
+	_, ok = r.ByEndpoint("200002")
+	assert.False(t, ok)
+	_, ok = r.Get("brand-b")
+	assert.False(t, ok)
+	assert.Equal(t, []string{"brand-a"}, r.Names())
+}
+
+func TestRegistry_Callback(t *testing.T) {
+	r := testRegistry(t)
+	a, _ := r.Get("brand-a")
+	b, _ := r.Get("brand-b")
+
+	//the extra endpoints of a merchant with an EndpointTable are not listed by its router
+	c := Merchant{
+		Name: "brand-c",
+		SDK: &SDK{
+			MerchantID:        "MERCHANT_C",
+			MerchantSecretKey: "SECRET_C",
+			EndpointID:        "300001",
+			ApiBaseURL:        SANDBOX,
+			EndpointRouter:    EndpointTable{{Currency: "THB", EndpointID: "300002"}},
+		},
+		Endpoints: []string{"300003"},
+	}
+	assert.Equal(t, nil, r.Add(c))
+
+	tests := []struct {
+		name     string
+		sdk      *SDK
+		endpoint string
+		merchant string
+		err      error
+	}{
+		{"merchant a", a.SDK, "100001", "brand-a", nil},
+		{"merchant a routed endpoint", a.SDK, "100002", "brand-a", nil},
+		{"merchant b extra endpoint", b.SDK, "200002", "brand-b", nil},
+		{"merchant c extra endpoint with a router", c.SDK, "300003", "brand-c", nil},
+		{"wrong merchant secret", a.SDK, "200001", "", fmt.Errorf("wrong signature")},
+		{"unknown endpoint", a.SDK, "999999", "", fmt.Errorf("unknown endpointID 999999")},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			m, c, err := r.Callback(signedCallback(test.sdk, test.endpoint))
+			assert.Equal(t, test.err, err)
+			assert.Equal(t, test.merchant, m.Name)
+			if err == nil {
+				assert.Equal(t, test.endpoint, c.EndpointID)
+			}
+		})
+	}
+
+	_, _, err := r.Callback([]byte("not json"))
+	assert.NotEqual(t, nil, err)
+}
+
+func TestRegistry_Redirect(t *testing.T) {
+	r := testRegistry(t)
+	b, _ := r.Get("brand-b")
+
+	q := url.Values{}
+	q.Set("status", "APPROVED")
+	q.Set("orderID", "1")
+	q.Set("merchantOrderID", "2")
+	q.Set("signature", b.SDK.sign("APPROVED", "1", "2"))
+	u := url.URL{Scheme: "https", Host: "merchant.com", Path: "/redirect", RawQuery: q.Encode()}
+
+	m, res, err := r.Redirect(u)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "brand-b", m.Name)
+	assert.Equal(t, "1", res.OrderID)
+
+	q.Set("endpointID", "200001")
+	u.RawQuery = q.Encode()
+	m, _, err = r.Redirect(u)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "brand-b", m.Name)
+
+	q.Set("endpointID", "100001")
+	u.RawQuery = q.Encode()
+	_, _, err = r.Redirect(u)
+	assert.Equal(t, fmt.Errorf("wrong signature"), err)
+
+	q.Del("endpointID")
+	q.Set("signature", "wrong")
+	u.RawQuery = q.Encode()
+	_, _, err = r.Redirect(u)
+	assert.Equal(t, fmt.Errorf("wrong signature"), err)
+}
@@ -0,0 +1,209 @@
+package zota
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// PartitionedCSVSinkConfig configures a PartitionedCSVSink
+// zero values are replaced by the defaults
+type PartitionedCSVSinkConfig struct {
+	// Partition returns the date partitioning a row, CreatedAt by default
+	Partition func(row ReportRow) time.Time
+	// FileName is the name of the file of a partition, "orders.csv.gz" by default
+	FileName string
+}
+
+// PartitionedCSVSink is a RowSink writing the rows to gzip compressed csv files partitioned by date
+// e.g. dir/dt=2020-08-05/orders.csv.gz, dates are in UTC
+// the files have the columns of the orders report followed by the extras and can be read with NewReportStream,
+// an order without an extra of the partition has it empty
+// the rows of a partition are kept in memory and written on Close to a temporary file renamed to the partition,
+// an order written several times keeps its last row
+// the rows of an existing partition are kept unless an order with the same ID is written again
+type PartitionedCSVSink struct {
+	dir   string
+	cfg   PartitionedCSVSinkConfig
+	files map[string]*partitionFile
+	paths map[string]bool
+}
+
+// partitionFile holds the rows of a partition until it is written
+// index maps the order ids to their row
+type partitionFile struct {
+	path  string
+	rows  []ReportRow
+	index map[int64]int
+}
+
+// NewPartitionedCSVSink creates a PartitionedCSVSink writing to dir
+func NewPartitionedCSVSink(dir string, cfg PartitionedCSVSinkConfig) *PartitionedCSVSink {
+	if cfg.Partition == nil {
+		cfg.Partition = func(row ReportRow) time.Time { return row.CreatedAt }
+	}
+	if cfg.FileName == "" {
+		cfg.FileName = "orders.csv.gz"
+	}
+	return &PartitionedCSVSink{dir: dir, cfg: cfg, files: map[string]*partitionFile{}, paths: map[string]bool{}}
+}
+
+// WriteRow implements RowSink
+func (p *PartitionedCSVSink) WriteRow(row ReportRow) error {
+	path := filepath.Join(p.dir, "dt="+p.cfg.Partition(row).UTC().Format(ReportDateLayout), p.cfg.FileName)
+	file, ok := p.files[path]
+	if !ok {
+		file = &partitionFile{path: path, index: map[int64]int{}}
+		p.files[path] = file
+		p.paths[path] = true
+	}
+	file.add(row)
+	return nil
+}
+
+// Close implements RowSink, writing the files of the partitions
+func (p *PartitionedCSVSink) Close() error {
+	var first error
+	for path, file := range p.files {
+		err := file.close()
+		if err != nil && first == nil {
+			first = err
+		}
+		delete(p.files, path)
+	}
+	return first
+}
+
+// Paths returns the paths of the partition files written by the sink, sorted
+// the files are complete once Close returns
+func (p *PartitionedCSVSink) Paths() []string {
+	paths := make([]string, 0, len(p.paths))
+	for path := range p.paths {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// add adds the row to the partition, replacing the row of the same order
+func (f *partitionFile) add(row ReportRow) {
+	i, ok := f.index[row.ID]
+	if ok {
+		f.rows[i] = row
+		return
+	}
+	f.index[row.ID] = len(f.rows)
+	f.rows = append(f.rows, row)
+}
+
+// merge adds the rows of the existing partition whose order was not written again
+func (f *partitionFile) merge() error {
+	existing, err := os.Open(f.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer existing.Close()
+	gz, err := gzip.NewReader(existing)
+	if err != nil {
+		return fmt.Errorf("unexpected partition %v:%v", f.path, err)
+	}
+
+	rows := NewReportStream(gz)
+	for rows.Next() {
+		row := rows.Row()
+		if _, ok := f.index[row.ID]; !ok {
+			f.add(row)
+		}
+	}
+	return rows.Err()
+}
+
+// close merges the existing partition, writes the rows to the temporary file
+// and renames it to its partition, the temporary file is removed on error
+func (f *partitionFile) close() error {
+	err := f.merge()
+	if err != nil {
+		return err
+	}
+	err = f.writeFile(f.path + ".tmp")
+	if err != nil {
+		os.Remove(f.path + ".tmp")
+		return err
+	}
+	err = os.Rename(f.path+".tmp", f.path)
+	if err != nil {
+		os.Remove(f.path + ".tmp")
+		return err
+	}
+	return syncDir(filepath.Dir(f.path))
+}
+
+// writeFile writes the header and the rows to path, flushed and synced
+func (f *partitionFile) writeFile(path string) error {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	gz := gzip.NewWriter(file)
+	err = f.writeRows(csv.NewWriter(gz))
+	if err == nil {
+		err = gz.Close()
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	closeErr := file.Close()
+	if err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// writeRows writes the header and the rows, the extras are the last columns sorted by name
+func (f *partitionFile) writeRows(w *csv.Writer) error {
+	extras := map[string]bool{}
+	for _, row := range f.rows {
+		for name := range row.Extras {
+			extras[name] = true
+		}
+	}
+	extraNames := sortedKeys(extras)
+
+	header := make([]string, 0, len(reportFields)+len(extraNames))
+	for _, field := range reportFields {
+		header = append(header, field.name)
+	}
+	err := w.Write(append(header, extraNames...))
+	if err != nil {
+		return err
+	}
+
+	for _, row := range f.rows {
+		v := reflect.ValueOf(row)
+		record := make([]string, 0, len(header)+len(extraNames))
+		for _, field := range reportFields {
+			record = append(record, formatReportField(v.Field(field.index)))
+		}
+		for _, name := range extraNames {
+			record = append(record, row.Extras[name])
+		}
+		err = w.Write(record)
+		if err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,148 @@
+package zota
+
+import (
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartitionedCSVSink(t *testing.T) {
+	dir := t.TempDir()
+	sink := NewPartitionedCSVSink(dir, PartitionedCSVSinkConfig{})
+
+	day1 := time.Date(2020, 8, 5, 13, 3, 30, 0, time.UTC)
+	day2 := time.Date(2020, 8, 6, 1, 0, 0, 0, time.UTC)
+	rows := []ReportRow{
+		{ID: 1, Status: StatusApproved, OrderAmount: MustParseMoney("500"), CreatedAt: day1, EndedAt: day1, CallbackSentToMerchant: true, CustomerBankAddress: "Thong Nai Pan Noi Beach, Baan Tai"},
+		{ID: 2, ParentID: 1, Status: StatusDeclined, CreatedAt: day2},
+		{ID: 3, Status: StatusPending, CreatedAt: day1.Add(time.Hour)},
+	}
+	for _, row := range rows {
+		assert.Equal(t, nil, sink.WriteRow(row))
+	}
+
+	paths := []string{
+		filepath.Join(dir, "dt=2020-08-05", "orders.csv.gz"),
+		filepath.Join(dir, "dt=2020-08-06", "orders.csv.gz"),
+	}
+	assert.Equal(t, paths, sink.Paths())
+	//the partitions are renamed on Close
+	_, err := os.Stat(paths[0])
+	assert.True(t, os.IsNotExist(err))
+
+	assert.Equal(t, nil, sink.Close())
+	assert.Equal(t, []ReportRow{rows[0], rows[2]}, readPartition(t, paths[0]))
+	assert.Equal(t, []ReportRow{rows[1]}, readPartition(t, paths[1]))
+	assert.Equal(t, paths, sink.Paths())
+}
+
+func TestPartitionedCSVSinkMerge(t *testing.T) {
+	dir := t.TempDir()
+	day := time.Date(2020, 8, 5, 13, 0, 0, 0, time.UTC)
+	path := filepath.Join(dir, "dt=2020-08-05", "orders.csv.gz")
+
+	sink := NewPartitionedCSVSink(dir, PartitionedCSVSinkConfig{})
+	assert.Equal(t, nil, sink.WriteRow(ReportRow{ID: 1, Status: StatusApproved, CreatedAt: day}))
+	assert.Equal(t, nil, sink.WriteRow(ReportRow{ID: 2, Status: StatusPending, CreatedAt: day}))
+	assert.Equal(t, nil, sink.Close())
+
+	//a later export of the same day replaces the orders written again and keeps the others
+	sink = NewPartitionedCSVSink(dir, PartitionedCSVSinkConfig{})
+	assert.Equal(t, nil, sink.WriteRow(ReportRow{ID: 2, Status: StatusDeclined, CreatedAt: day}))
+	assert.Equal(t, nil, sink.WriteRow(ReportRow{ID: 3, Status: StatusPending, CreatedAt: day}))
+	assert.Equal(t, nil, sink.Close())
+	assert.Equal(t, []string{path}, sink.Paths())
+
+	rows := readPartition(t, path)
+	assert.Equal(t, []ReportRow{
+		{ID: 2, Status: StatusDeclined, CreatedAt: day},
+		{ID: 3, Status: StatusPending, CreatedAt: day},
+		{ID: 1, Status: StatusApproved, CreatedAt: day},
+	}, rows)
+}
+
+func TestPartitionedCSVSinkLastRow(t *testing.T) {
+	dir := t.TempDir()
+	day := time.Date(2020, 8, 5, 13, 0, 0, 0, time.UTC)
+	path := filepath.Join(dir, "dt=2020-08-05", "orders.csv.gz")
+
+	//an order written several times in a run keeps its last row
+	sink := NewPartitionedCSVSink(dir, PartitionedCSVSinkConfig{})
+	assert.Equal(t, nil, sink.WriteRow(ReportRow{ID: 1, Status: StatusPending, CreatedAt: day}))
+	assert.Equal(t, nil, sink.WriteRow(ReportRow{ID: 2, Status: StatusPending, CreatedAt: day}))
+	assert.Equal(t, nil, sink.WriteRow(ReportRow{ID: 1, Status: StatusApproved, CreatedAt: day}))
+	assert.Equal(t, nil, sink.Close())
+
+	assert.Equal(t, []ReportRow{
+		{ID: 1, Status: StatusApproved, CreatedAt: day},
+		{ID: 2, Status: StatusPending, CreatedAt: day},
+	}, readPartition(t, path))
+}
+
+func TestPartitionedCSVSinkExtras(t *testing.T) {
+	dir := t.TempDir()
+	day := time.Date(2020, 8, 5, 13, 0, 0, 0, time.UTC)
+	path := filepath.Join(dir, "dt=2020-08-05", "orders.csv.gz")
+
+	sink := NewPartitionedCSVSink(dir, PartitionedCSVSinkConfig{})
+	assert.Equal(t, nil, sink.WriteRow(ReportRow{ID: 1, CreatedAt: day, Extras: map[string]string{"b": "2", "a": "1"}}))
+	assert.Equal(t, nil, sink.WriteRow(ReportRow{ID: 2, CreatedAt: day, Extras: map[string]string{"a": "3"}}))
+	assert.Equal(t, nil, sink.Close())
+
+	//the extras are written as columns, empty for the orders without them
+	assert.Equal(t, []ReportRow{
+		{ID: 1, CreatedAt: day, Extras: map[string]string{"a": "1", "b": "2"}},
+		{ID: 2, CreatedAt: day, Extras: map[string]string{"a": "3", "b": ""}},
+	}, readPartition(t, path))
+}
+
+func TestPartitionedCSVSinkError(t *testing.T) {
+	dir := t.TempDir()
+	day := time.Date(2020, 8, 5, 13, 0, 0, 0, time.UTC)
+	path := filepath.Join(dir, "dt=2020-08-05", "orders.csv.gz")
+	assert.Equal(t, nil, os.MkdirAll(filepath.Dir(path), 0755))
+	assert.Equal(t, nil, os.WriteFile(path, []byte("not gzip"), 0644))
+
+	//the existing partition is kept and no temporary file is left
+	sink := NewPartitionedCSVSink(dir, PartitionedCSVSinkConfig{})
+	assert.Equal(t, nil, sink.WriteRow(ReportRow{ID: 1, CreatedAt: day}))
+	assert.NotEqual(t, nil, sink.Close())
+	b, err := os.ReadFile(path)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "not gzip", string(b))
+	_, err = os.Stat(path + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestPartitionedCSVSinkConfig(t *testing.T) {
+	dir := t.TempDir()
+	sink := NewPartitionedCSVSink(dir, PartitionedCSVSinkConfig{
+		Partition: func(row ReportRow) time.Time { return row.EndedAt },
+		FileName:  "payouts.csv.gz",
+	})
+
+	tz := time.FixedZone("UTC+8", 8*60*60)
+	row := ReportRow{ID: 1, EndedAt: time.Date(2020, 8, 6, 2, 0, 0, 0, tz)}
+	assert.Equal(t, nil, sink.WriteRow(row))
+	assert.Equal(t, nil, sink.Close())
+
+	rows := readPartition(t, filepath.Join(dir, "dt=2020-08-05", "payouts.csv.gz"))
+	assert.Equal(t, 1, len(rows))
+	assert.True(t, row.EndedAt.Equal(rows[0].EndedAt))
+}
+
+// readPartition parses the rows of a partition file
+func readPartition(t *testing.T, path string) []ReportRow {
+	f, err := os.Open(path)
+	assert.Equal(t, nil, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	assert.Equal(t, nil, err)
+	rows, err := ParseOrdersReport(gz)
+	assert.Equal(t, nil, err)
+	return rows
+}
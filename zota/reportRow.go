@@ -0,0 +1,248 @@
+package zota
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ReportTimeLayout is the layout of the times of the orders report
+// e.g. "2020-08-05 13:03:30 +0000 UTC"
+const ReportTimeLayout = "2006-01-02 15:04:05 -0700 MST"
+
+// ReportRow represents an order of the orders report
+// the csv columns are mapped by the json name of the fields
+// columns unknown to ReportRow are kept in Extras
+// empty ids, amounts and times are zero values
+type ReportRow struct {
+	ID                         int64           `json:"id"`
+	ParentID                   int64           `json:"parent_id"`
+	OrderType                  string          `json:"order_type"`
+	Status                     OrderStatusCode `json:"status"`
+	MerchantID                 string          `json:"merchant_id"`
+	BatchID                    int64           `json:"batch_id"`
+	EndpointID                 string          `json:"endpoint_id"`
+	EndpointGroupID            int64           `json:"endpoint_group_id"`
+	OrderCurrency              string          `json:"order_currency"`
+	OrderAmount                Money           `json:"order_amount"`
+	OriginalAmount             Money           `json:"original_amount"`
+	AmountChanged              bool            `json:"amount_changed"`
+	MerchantOrderID            string          `json:"merchant_order_id"`
+	PaymentMethodID            string          `json:"payment_method_id"`
+	ExternalTransactionID      string          `json:"external_transaction_id"`
+	ClientErrorMessage         string          `json:"client_error_message"`
+	StatusChanged              bool            `json:"status_changed"`
+	CreatedAt                  time.Time       `json:"created_at"`
+	EndedAt                    time.Time       `json:"ended_at"`
+	EndedWithStatus            OrderStatusCode `json:"ended_with_status"`
+	LastUpdateAt               time.Time       `json:"last_update_at"`
+	IsRefunded                 bool            `json:"is_refunded"`
+	IsFullyRefunded            bool            `json:"is_fully_refunded"`
+	RefundedAmount             Money           `json:"refunded_amount"`
+	RefundedAt                 time.Time       `json:"refunded_at"`
+	RequestCustomerIP          string          `json:"request_customer_ip"`
+	EnteredDepositURL          bool            `json:"entered_deposit_url"`
+	EnteredBankSelectionPage   bool            `json:"entered_bank_selection_page"`
+	BankSelected               bool            `json:"bank_selected"`
+	SelectedBankCode           string          `json:"selected_bank_code"`
+	SelectedBankName           string          `json:"selected_bank_name"`
+	CallbackSentToMerchant     bool            `json:"callback_sent_to_merchant"`
+	CallbackReceivedByMerchant bool            `json:"callback_received_by_merchant"`
+	CustomerEmail              string          `json:"customer_email"`
+	CustomerFirstName          string          `json:"customer_first_name"`
+	CustomerLastName           string          `json:"customer_last_name"`
+	CustomerAddress            string          `json:"customer_address"`
+	CustomerCountryCode        string          `json:"customer_country_code"`
+	CustomerCity               string          `json:"customer_city"`
+	CustomerState              string          `json:"customer_state"`
+	CustomerZipCode            string          `json:"customer_zip_code"`
+	CustomerPhone              string          `json:"customer_phone"`
+	CustomerBankCode           string          `json:"customer_bank_code"`
+	CustomerBankAccountNumber  string          `json:"customer_bank_account_number"`
+	CustomerBankAccountName    string          `json:"customer_bank_account_name"`
+	CustomerBankBranch         string          `json:"customer_bank_branch"`
+	CustomerBankAddress        string          `json:"customer_bank_address"`
+	CustomerBankZipCode        string          `json:"customer_bank_zip_code"`
+	CustomerBankRoutingNumber  string          `json:"customer_bank_routing_number"`
+	CustomerBankProvince       string          `json:"customer_bank_province"`
+	CustomerBankArea           string          `json:"customer_bank_area"`
+
+	Extras map[string]string `json:"extras,omitempty"`
+}
+
+// ParseReportTime parses a time of the orders report
+// an empty time and "0001-01-01 00:00:00 +0000 UTC" are the zero time
+func ParseReportTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(ReportTimeLayout, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q", s)
+	}
+	if t.IsZero() {
+		return time.Time{}, nil
+	}
+	return t, nil
+}
+
+// ParseOrdersReport parses the csv orders report into rows
+func ParseOrdersReport(r io.Reader) ([]ReportRow, error) {
+	stream := NewReportStream(ioutil.NopCloser(r))
+	var rows []ReportRow
+	for stream.Next() {
+		rows = append(rows, stream.Row())
+	}
+	if stream.Err() != nil {
+		return nil, stream.Err()
+	}
+	return rows, nil
+}
+
+// Rows parses the csv orders report of the result into rows
+func (r OrdersReportResult) Rows() ([]ReportRow, error) {
+	return ParseOrdersReport(strings.NewReader(r.OrdersReport))
+}
+
+// reportField is a column of the orders report
+type reportField struct {
+	name  string
+	index int
+}
+
+// reportFields are the columns of ReportRow in field order, without the extras
+var reportFields = func() []reportField {
+	var fields []reportField
+	t := reflect.TypeOf(ReportRow{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "extras" {
+			fields = append(fields, reportField{name: name, index: i})
+		}
+	}
+	return fields
+}()
+
+// formatReportField formats a ReportRow field as in the csv orders report
+// zero ids and times are empty, times are in UTC
+func formatReportField(f reflect.Value) string {
+	switch v := f.Interface().(type) {
+	case Money:
+		return v.String()
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.UTC().Format(ReportTimeLayout)
+	}
+
+	switch f.Kind() {
+	case reflect.Int64:
+		if f.Int() == 0 {
+			return ""
+		}
+		return strconv.FormatInt(f.Int(), 10)
+	case reflect.Bool:
+		return strconv.FormatBool(f.Bool())
+	}
+	return f.String()
+}
+
+// reportColumns maps the json names of ReportRow to the field indexes
+var reportColumns = func() map[string]int {
+	columns := map[string]int{}
+	for _, f := range reportFields {
+		columns[f.name] = f.index
+	}
+	return columns
+}()
+
+// reportDecoder decodes the csv records of an orders report with the header
+type reportDecoder struct {
+	header []string
+	fields []int
+}
+
+// newReportDecoder maps the header columns to the ReportRow fields, -1 for the extras
+func newReportDecoder(header []string) *reportDecoder {
+	d := &reportDecoder{header: header, fields: make([]int, len(header))}
+	for i, name := range header {
+		field, ok := reportColumns[strings.TrimSpace(name)]
+		if !ok {
+			field = -1
+		}
+		d.fields[i] = field
+	}
+	return d
+}
+
+// decode decodes a csv record into a ReportRow
+func (d *reportDecoder) decode(record []string) (row ReportRow, err error) {
+	if len(record) != len(d.header) {
+		return row, fmt.Errorf("has %v columns, expected %v", len(record), len(d.header))
+	}
+
+	v := reflect.ValueOf(&row).Elem()
+	for i, value := range record {
+		if d.fields[i] < 0 {
+			if row.Extras == nil {
+				row.Extras = map[string]string{}
+			}
+			row.Extras[d.header[i]] = value
+			continue
+		}
+		err = setReportField(v.Field(d.fields[i]), value)
+		if err != nil {
+			return row, fmt.Errorf("column %v: %v", d.header[i], err)
+		}
+	}
+	return row, nil
+}
+
+// setReportField parses value into the ReportRow field
+func setReportField(f reflect.Value, value string) error {
+	switch f.Interface().(type) {
+	case Money:
+		m, err := ParseMoney(value)
+		if err != nil {
+			return err
+		}
+		f.Set(reflect.ValueOf(m))
+		return nil
+	case time.Time:
+		t, err := ParseReportTime(value)
+		if err != nil {
+			return err
+		}
+		f.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(value)
+	case reflect.Int64:
+		if value == "" {
+			return nil
+		}
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid id %q", value)
+		}
+		f.SetInt(n)
+	case reflect.Bool:
+		if value == "" {
+			return nil
+		}
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid bool %q", value)
+		}
+		f.SetBool(b)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package zota
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReportTime(t *testing.T) {
+	tm, err := ParseReportTime("2020-08-05 13:03:30 +0000 UTC")
+	assert.Equal(t, nil, err)
+	assert.Equal(t, time.Date(2020, 8, 5, 13, 3, 30, 0, time.UTC), tm.UTC())
+
+	tm, err = ParseReportTime("0001-01-01 00:00:00 +0000 UTC")
+	assert.Equal(t, nil, err)
+	assert.True(t, tm.IsZero())
+
+	_, err = ParseReportTime("2020-08-05T13:03:30Z")
+	assert.Equal(t, fmt.Errorf("invalid time %q", "2020-08-05T13:03:30Z"), err)
+}
+
+func TestOrdersReportResult_Rows(t *testing.T) {
+	sdk := testPollSDK(&ClientMockOrdersRepSuccess{})
+	res, err := sdk.OrdersReport(OrdersReport{DateType: "created", FromDate: "2020-08-01", ToDate: "2020-08-06"})
+	assert.Equal(t, nil, err)
+
+	rows, err := res.Rows()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 1, len(rows))
+
+	row := rows[0]
+	assert.Equal(t, int64(24050211), row.ID)
+	assert.Equal(t, int64(0), row.ParentID)
+	assert.Equal(t, "PAYOUT", row.OrderType)
+	assert.Equal(t, StatusDeclined, row.Status)
+	assert.Equal(t, "503368", row.EndpointID)
+	assert.Equal(t, MustParseMoney("500"), row.OrderAmount)
+	assert.Equal(t, MustParseMoney("500"), row.OriginalAmount)
+	assert.Equal(t, false, row.AmountChanged)
+	assert.Equal(t, "TbbQzewLWwDW6goc", row.MerchantOrderID)
+	assert.Equal(t, "insufficient funds", row.ClientErrorMessage)
+	assert.Equal(t, time.Date(2020, 8, 5, 13, 3, 30, 0, time.UTC), row.CreatedAt.UTC())
+	assert.Equal(t, StatusDeclined, row.EndedWithStatus)
+	assert.True(t, row.RefundedAt.IsZero())
+	assert.True(t, row.RefundedAmount.IsZero())
+	assert.Equal(t, true, row.CallbackSentToMerchant)
+	assert.Equal(t, "Thong Nai Pan Noi Beach, Baan Tai, Koh Phangan", row.CustomerBankAddress)
+	assert.Equal(t, "Bank Area / City", row.CustomerBankArea)
+	assert.Nil(t, row.Extras)
+}
+
+func TestParseOrdersReport(t *testing.T) {
+	//reordered and unknown columns
+	rows, err := ParseOrdersReport(strings.NewReader("status,new_column,merchant_order_id,id,is_refunded\n" +
+		"APPROVED,x,m-1,1,true\n" +
+		"PENDING,,m-2,2,\n"))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []ReportRow{
+		{ID: 1, Status: StatusApproved, MerchantOrderID: "m-1", IsRefunded: true, Extras: map[string]string{"new_column": "x"}},
+		{ID: 2, Status: StatusPending, MerchantOrderID: "m-2", Extras: map[string]string{"new_column": ""}},
+	}, rows)
+
+	rows, err = ParseOrdersReport(strings.NewReader(""))
+	assert.Equal(t, nil, err)
+	assert.Nil(t, rows)
+
+	tests := map[string]string{
+		"id\n1\nx\n":               `orders report line 3: column id: invalid id "x"`,
+		"id,is_refunded\n1,yes\n":  `orders report line 2: column is_refunded: invalid bool "yes"`,
+		"order_amount\n1,5\n":      `orders report line 2: has 2 columns, expected 1`,
+		"order_amount\n1.5.0\n":    `orders report line 2: column order_amount: invalid amount "1.5.0"`,
+		"created_at\n2020-08-05\n": `orders report line 2: column created_at: invalid time "2020-08-05"`,
+	}
+	for csv, expected := range tests {
+		_, err := ParseOrdersReport(strings.NewReader(csv))
+		assert.Equal(t, expected, err.Error(), csv)
+	}
+
+	for _, csv := range []string{"id,status\n1,\"APPROVED\n", "\"id\n"} {
+		_, err := ParseOrdersReport(strings.NewReader(csv))
+		assert.True(t, strings.HasPrefix(err.Error(), "unexpected orders report csv:"), csv)
+	}
+}
@@ -0,0 +1,241 @@
+package zota
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SQLSinkConfig configures a SQLSink
+// zero values are replaced by the defaults
+type SQLSinkConfig struct {
+	// Table is the name of the table, "zota_orders" by default
+	Table string
+	// Placeholder returns the placeholder of the nth argument, starting at 1, "?" by default
+	// e.g. DollarPlaceholder for PostgreSQL
+	Placeholder func(n int) string
+	// BatchSize is the number of rows upserted in a transaction, 500 by default
+	BatchSize int
+}
+
+// DollarPlaceholder returns the placeholder $n, as used by PostgreSQL
+func DollarPlaceholder(n int) string {
+	return "$" + strconv.Itoa(n)
+}
+
+// sqlTableName matches the table names accepted by SQLSink
+var sqlTableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// SQLSink is a RowSink and a ReportSink upserting the rows by order ID into a database/sql table
+// the table has a column per report column, an extras column with the json of the extras
+// and a row_hash column with the hash of the values, to tell whether an upserted row changed
+// the upsert only uses portable statements so it works with any driver
+// the rows of WriteRow are written in batches, Close writes the last batch but does not close the db
+type SQLSink struct {
+	db    *sql.DB
+	cfg   SQLSinkConfig
+	batch []ReportRow
+
+	exists string
+	insert string
+	update string
+}
+
+// NewSQLSink creates a SQLSink, creating the table if it does not exist
+func NewSQLSink(ctx context.Context, db *sql.DB, cfg SQLSinkConfig) (*SQLSink, error) {
+	if cfg.Table == "" {
+		cfg.Table = "zota_orders"
+	}
+	if !sqlTableName.MatchString(cfg.Table) {
+		return nil, fmt.Errorf("invalid table name %q", cfg.Table)
+	}
+	if cfg.Placeholder == nil {
+		cfg.Placeholder = func(int) string { return "?" }
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 500
+	}
+
+	columns := make([]string, 0, len(reportFields)+1)
+	definitions := make([]string, 0, len(reportFields)+1)
+	t := reflect.TypeOf(ReportRow{})
+	for _, f := range reportFields {
+		columns = append(columns, f.name)
+		definitions = append(definitions, f.name+" "+sqlColumnType(f.name, t.Field(f.index).Type))
+	}
+	columns = append(columns, "extras", "row_hash")
+	definitions = append(definitions, "extras TEXT", "row_hash TEXT")
+
+	_, err := db.ExecContext(ctx, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %v (%v)", cfg.Table, strings.Join(definitions, ", ")))
+	if err != nil {
+		return nil, err
+	}
+
+	//the id is the first argument of the insert and the last of the update
+	placeholders := make([]string, len(columns))
+	sets := make([]string, 0, len(columns)-1)
+	for i, column := range columns {
+		placeholders[i] = cfg.Placeholder(i + 1)
+		if i > 0 {
+			sets = append(sets, column+" = "+cfg.Placeholder(i))
+		}
+	}
+	return &SQLSink{
+		db:     db,
+		cfg:    cfg,
+		exists: fmt.Sprintf("SELECT row_hash FROM %v WHERE id = %v", cfg.Table, cfg.Placeholder(1)),
+		insert: fmt.Sprintf("INSERT INTO %v (%v) VALUES (%v)", cfg.Table, strings.Join(columns, ", "), strings.Join(placeholders, ", ")),
+		update: fmt.Sprintf("UPDATE %v SET %v WHERE id = %v", cfg.Table, strings.Join(sets, ", "), cfg.Placeholder(len(columns))),
+	}, nil
+}
+
+// WriteRow implements RowSink, the row is upserted once the batch is full
+func (s *SQLSink) WriteRow(row ReportRow) error {
+	s.batch = append(s.batch, row)
+	if len(s.batch) < s.cfg.BatchSize {
+		return nil
+	}
+	return s.Flush(context.Background())
+}
+
+// Close implements RowSink, upserting the last batch
+func (s *SQLSink) Close() error {
+	return s.Flush(context.Background())
+}
+
+// Upsert implements ReportSink, upserting the row in its own transaction
+// a row is changed if it is new or its values differ from the stored ones
+// the batch of WriteRow is not flushed
+func (s *SQLSink) Upsert(row ReportRow) (bool, error) {
+	ctx := context.Background()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	changed, err := s.upsert(ctx, tx, row)
+	if err != nil {
+		tx.Rollback()
+		return false, fmt.Errorf("upsert order %v: %v", row.ID, err)
+	}
+	return changed, tx.Commit()
+}
+
+// Flush upserts the rows of the batch in a transaction
+func (s *SQLSink) Flush(ctx context.Context) error {
+	if len(s.batch) == 0 {
+		return nil
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for _, row := range s.batch {
+		_, err = s.upsert(ctx, tx, row)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("upsert order %v: %v", row.ID, err)
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+	s.batch = s.batch[:0]
+	return nil
+}
+
+// upsert updates the row if the id exists with another hash, inserts it if the id does not exist
+// returns whether the row was inserted or updated
+func (s *SQLSink) upsert(ctx context.Context, tx *sql.Tx, row ReportRow) (bool, error) {
+	values, err := sqlValues(row)
+	if err != nil {
+		return false, err
+	}
+	b, err := json.Marshal(values)
+	if err != nil {
+		return false, err
+	}
+	sum := sha256.Sum256(b)
+	hash := hex.EncodeToString(sum[:])
+	values = append(values, hash)
+
+	var stored sql.NullString
+	err = tx.QueryRowContext(ctx, s.exists, row.ID).Scan(&stored)
+	if errors.Is(err, sql.ErrNoRows) {
+		_, err = tx.ExecContext(ctx, s.insert, values...)
+		return err == nil, err
+	}
+	if err != nil {
+		return false, err
+	}
+	if stored.Valid && stored.String == hash {
+		return false, nil
+	}
+	_, err = tx.ExecContext(ctx, s.update, append(values[1:], row.ID)...)
+	return err == nil, err
+}
+
+// sqlColumnType returns the portable sql type of a report column
+func sqlColumnType(name string, t reflect.Type) string {
+	switch {
+	case name == "id":
+		return "BIGINT NOT NULL PRIMARY KEY"
+	case t == reflect.TypeOf(Money{}):
+		return "DECIMAL(30,8)"
+	case t == reflect.TypeOf(time.Time{}):
+		return "TIMESTAMP"
+	case t.Kind() == reflect.Int64:
+		return "BIGINT"
+	case t.Kind() == reflect.Bool:
+		return "BOOLEAN"
+	}
+	return "TEXT"
+}
+
+// sqlValues returns the values of the columns of the row, in the order of the table
+// zero ids and times are NULL
+func sqlValues(row ReportRow) ([]interface{}, error) {
+	v := reflect.ValueOf(row)
+	values := make([]interface{}, 0, len(reportFields)+1)
+	for _, f := range reportFields {
+		field := v.Field(f.index)
+		switch value := field.Interface().(type) {
+		case Money:
+			values = append(values, value.String())
+		case time.Time:
+			if value.IsZero() {
+				values = append(values, nil)
+			} else {
+				values = append(values, value.UTC())
+			}
+		case int64:
+			if value == 0 && f.name != "id" {
+				values = append(values, nil)
+			} else {
+				values = append(values, value)
+			}
+		case bool:
+			values = append(values, value)
+		default:
+			values = append(values, field.String())
+		}
+	}
+
+	if len(row.Extras) == 0 {
+		return append(values, nil), nil
+	}
+	extras, err := json.Marshal(row.Extras)
+	if err != nil {
+		return nil, err
+	}
+	return append(values, string(extras)), nil
+}
@@ -0,0 +1,204 @@
+package zota
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeDB is an in memory database understanding the statements of SQLSink
+// implement driver.Connector
+type fakeDB struct {
+	mu         sync.Mutex
+	statements []string
+	rows       map[int64][]driver.Value
+	commits    int
+	failInsert bool
+}
+
+func (f *fakeDB) Connect(context.Context) (driver.Conn, error) { return &fakeConn{db: f}, nil }
+func (f *fakeDB) Driver() driver.Driver                        { return nil }
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return &fakeStmt{db: c.db, query: query}, nil
+}
+func (c *fakeConn) Close() error              { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) { return &fakeTx{db: c.db}, nil }
+
+type fakeTx struct {
+	db *fakeDB
+}
+
+func (t *fakeTx) Commit() error {
+	t.db.mu.Lock()
+	defer t.db.mu.Unlock()
+	t.db.commits++
+	return nil
+}
+func (t *fakeTx) Rollback() error { return nil }
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s *fakeStmt) Close() error  { return nil }
+func (s *fakeStmt) NumInput() int { return -1 }
+
+func (s *fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	s.db.statements = append(s.db.statements, s.query)
+	switch {
+	case strings.HasPrefix(s.query, "INSERT"):
+		if s.db.failInsert {
+			return nil, fmt.Errorf("insert failed")
+		}
+		s.db.rows[args[0].(int64)] = args
+	case strings.HasPrefix(s.query, "UPDATE"):
+		id := args[len(args)-1].(int64)
+		s.db.rows[id] = append([]driver.Value{id}, args[:len(args)-1]...)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s *fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	s.db.statements = append(s.db.statements, s.query)
+	row, ok := s.db.rows[args[0].(int64)]
+	return &fakeRows{row: row, left: ok}, nil
+}
+
+// fakeRows returns the row_hash of the row
+type fakeRows struct {
+	row  []driver.Value
+	left bool
+}
+
+func (r *fakeRows) Columns() []string { return []string{"row_hash"} }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if !r.left {
+		return io.EOF
+	}
+	r.left = false
+	dest[0] = r.row[len(r.row)-1]
+	return nil
+}
+
+func TestSQLSink(t *testing.T) {
+	fake := &fakeDB{rows: map[int64][]driver.Value{}}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+
+	sink, err := NewSQLSink(context.Background(), db, SQLSinkConfig{BatchSize: 2})
+	assert.Equal(t, nil, err)
+	assert.True(t, strings.HasPrefix(fake.statements[0], "CREATE TABLE IF NOT EXISTS zota_orders (id BIGINT NOT NULL PRIMARY KEY, parent_id BIGINT, order_type TEXT, status TEXT,"))
+	assert.True(t, strings.Contains(fake.statements[0], "order_amount DECIMAL(30,8)"))
+	assert.True(t, strings.Contains(fake.statements[0], "created_at TIMESTAMP"))
+	assert.True(t, strings.HasSuffix(fake.statements[0], "customer_bank_area TEXT, extras TEXT, row_hash TEXT)"))
+
+	created := time.Date(2020, 8, 5, 13, 3, 30, 0, time.UTC)
+	assert.Equal(t, nil, sink.WriteRow(ReportRow{ID: 1, Status: StatusPending, OrderAmount: MustParseMoney("500"), CreatedAt: created}))
+	//the first batch is not full
+	assert.Equal(t, 1, len(fake.statements))
+
+	assert.Equal(t, nil, sink.WriteRow(ReportRow{ID: 2, Status: StatusApproved, Extras: map[string]string{"custom": "x"}}))
+	assert.Equal(t, 1, fake.commits)
+	assert.Equal(t, []string{sink.exists, sink.insert, sink.exists, sink.insert}, fake.statements[1:])
+
+	row := fake.rows[1]
+	assert.Equal(t, len(reportFields)+2, len(row))
+	assert.Equal(t, int64(1), row[0])
+	assert.Equal(t, nil, row[1])
+	assert.Equal(t, "PENDING", row[3])
+	assert.Equal(t, "500.00000000", row[9])
+	assert.Equal(t, created, row[17])
+	assert.Equal(t, nil, row[18])
+	assert.Equal(t, nil, row[len(row)-2])
+	assert.Equal(t, `{"custom":"x"}`, fake.rows[2][len(row)-2])
+
+	//the existing order is updated
+	assert.Equal(t, nil, sink.WriteRow(ReportRow{ID: 1, Status: StatusApproved, CreatedAt: created}))
+	assert.Equal(t, nil, sink.Close())
+	assert.Equal(t, 2, fake.commits)
+	assert.Equal(t, []string{sink.exists, sink.update}, fake.statements[5:])
+	assert.Equal(t, "APPROVED", fake.rows[1][3])
+	assert.Equal(t, int64(1), fake.rows[1][0])
+	assert.Equal(t, 2, len(fake.rows))
+}
+
+func TestSQLSink_Upsert(t *testing.T) {
+	fake := &fakeDB{rows: map[int64][]driver.Value{}}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+
+	var sink ReportSink
+	sink, err := NewSQLSink(context.Background(), db, SQLSinkConfig{})
+	assert.Equal(t, nil, err)
+
+	row := ReportRow{ID: 1, Status: StatusPending, OrderAmount: MustParseMoney("500")}
+	changed, err := sink.Upsert(row)
+	assert.Equal(t, nil, err)
+	assert.True(t, changed)
+
+	//the same values are not written again
+	changed, err = sink.Upsert(row)
+	assert.Equal(t, nil, err)
+	assert.False(t, changed)
+
+	row.Status = StatusApproved
+	changed, err = sink.Upsert(row)
+	assert.Equal(t, nil, err)
+	assert.True(t, changed)
+	assert.Equal(t, "APPROVED", fake.rows[1][3])
+	assert.Equal(t, 3, fake.commits)
+	assert.Equal(t, 6, len(fake.statements))
+}
+
+func TestSQLSinkStatements(t *testing.T) {
+	fake := &fakeDB{rows: map[int64][]driver.Value{}}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+
+	sink, err := NewSQLSink(context.Background(), db, SQLSinkConfig{Table: "reports.orders", Placeholder: DollarPlaceholder})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "SELECT row_hash FROM reports.orders WHERE id = $1", sink.exists)
+	assert.True(t, strings.HasPrefix(sink.insert, "INSERT INTO reports.orders (id, parent_id, order_type, "))
+	assert.True(t, strings.HasSuffix(sink.insert, "VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26, $27, $28, $29, $30, $31, $32, $33, $34, $35, $36, $37, $38, $39, $40, $41, $42, $43, $44, $45, $46, $47, $48, $49, $50, $51, $52, $53)"))
+	assert.True(t, strings.HasPrefix(sink.update, "UPDATE reports.orders SET parent_id = $1, order_type = $2, "))
+	assert.True(t, strings.HasSuffix(sink.update, "extras = $51, row_hash = $52 WHERE id = $53"))
+
+	_, err = NewSQLSink(context.Background(), db, SQLSinkConfig{Table: "orders; DROP TABLE x"})
+	assert.Equal(t, fmt.Errorf("invalid table name %q", "orders; DROP TABLE x"), err)
+}
+
+func TestSQLSinkError(t *testing.T) {
+	fake := &fakeDB{rows: map[int64][]driver.Value{}, failInsert: true}
+	db := sql.OpenDB(fake)
+	defer db.Close()
+
+	sink, err := NewSQLSink(context.Background(), db, SQLSinkConfig{})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, nil, sink.WriteRow(ReportRow{ID: 7}))
+	assert.Equal(t, fmt.Errorf("upsert order 7: insert failed"), sink.Close())
+	assert.Equal(t, 0, fake.commits)
+
+	//the batch is kept to be retried
+	fake.failInsert = false
+	assert.Equal(t, nil, sink.Close())
+	assert.Equal(t, 1, len(fake.rows))
+}
@@ -0,0 +1,143 @@
+package zota
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+)
+
+// RowSink receives the rows of an orders report, e.g. to export them
+// Close flushes the rows written and releases the sink
+type RowSink interface {
+	WriteRow(row ReportRow) error
+	Close() error
+}
+
+// RowIterator iterates over report rows, implemented by ReportStream and ReportRange
+type RowIterator interface {
+	Next() bool
+	Row() ReportRow
+	Err() error
+}
+
+// CopyRows writes the rows of src to dst until src is done
+// returns the number of rows written, dst is not closed
+func CopyRows(dst RowSink, src RowIterator) (int, error) {
+	n := 0
+	for src.Next() {
+		err := dst.WriteRow(src.Row())
+		if err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, src.Err()
+}
+
+// UpsertRows returns a ReportSink writing every row to sink, e.g. to export the rows of a ReportSyncer
+// a RowSink cannot tell whether a row changed, so every row is reported as changed
+// sink is not closed
+func UpsertRows(sink RowSink) ReportSink {
+	return rowSinkUpserter{sink}
+}
+
+// rowSinkUpserter is a ReportSink writing to a RowSink
+type rowSinkUpserter struct {
+	sink RowSink
+}
+
+// Upsert implements ReportSink
+func (u rowSinkUpserter) Upsert(row ReportRow) (bool, error) {
+	err := u.sink.WriteRow(row)
+	return err == nil, err
+}
+
+// FilterRows returns a RowSink writing to next the rows keep returns true for
+func FilterRows(next RowSink, keep func(row ReportRow) bool) RowSink {
+	return &chainSink{next: next, fn: func(row ReportRow) (ReportRow, bool, error) {
+		return row, keep(row), nil
+	}}
+}
+
+// TransformRows returns a RowSink writing to next the rows returned by transform
+// e.g. to mask the customer data
+func TransformRows(next RowSink, transform func(row ReportRow) (ReportRow, error)) RowSink {
+	return &chainSink{next: next, fn: func(row ReportRow) (ReportRow, bool, error) {
+		row, err := transform(row)
+		return row, err == nil, err
+	}}
+}
+
+// chainSink applies fn to the rows before writing them to next
+type chainSink struct {
+	next RowSink
+	fn   func(row ReportRow) (ReportRow, bool, error)
+}
+
+// WriteRow implements RowSink
+func (c *chainSink) WriteRow(row ReportRow) error {
+	row, ok, err := c.fn(row)
+	if err != nil || !ok {
+		return err
+	}
+	return c.next.WriteRow(row)
+}
+
+// Close implements RowSink
+func (c *chainSink) Close() error {
+	return c.next.Close()
+}
+
+// MultiSink returns a RowSink writing every row to all the sinks
+func MultiSink(sinks ...RowSink) RowSink {
+	return multiSink(sinks)
+}
+
+// multiSink writes the rows to all the sinks
+type multiSink []RowSink
+
+// WriteRow implements RowSink
+func (m multiSink) WriteRow(row ReportRow) error {
+	for _, sink := range m {
+		err := sink.WriteRow(row)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close implements RowSink, closing all the sinks
+func (m multiSink) Close() error {
+	var first error
+	for _, sink := range m {
+		err := sink.Close()
+		if err != nil && first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// JSONLinesSink is a RowSink writing the rows as JSON Lines, one json object per row
+type JSONLinesSink struct {
+	w   *bufio.Writer
+	enc *json.Encoder
+}
+
+// NewJSONLinesSink creates a JSONLinesSink writing to w
+// Close flushes the rows but does not close w
+func NewJSONLinesSink(w io.Writer) *JSONLinesSink {
+	b := bufio.NewWriter(w)
+	return &JSONLinesSink{w: b, enc: json.NewEncoder(b)}
+}
+
+// WriteRow implements RowSink
+func (j *JSONLinesSink) WriteRow(row ReportRow) error {
+	return j.enc.Encode(row)
+}
+
+// Close implements RowSink
+func (j *JSONLinesSink) Close() error {
+	return j.w.Flush()
+}
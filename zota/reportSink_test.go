@@ -0,0 +1,107 @@
+package zota
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testRowSink collects the rows written
+type testRowSink struct {
+	rows   []ReportRow
+	closed bool
+	err    error
+}
+
+func (t *testRowSink) WriteRow(row ReportRow) error {
+	if t.err != nil {
+		return t.err
+	}
+	t.rows = append(t.rows, row)
+	return nil
+}
+
+func (t *testRowSink) Close() error {
+	t.closed = true
+	return nil
+}
+
+func TestCopyRows(t *testing.T) {
+	report := "id,status,order_amount\n1,APPROVED,1.50\n2,DECLINED,2.00\n3,APPROVED,3.00\n"
+	sink := &testRowSink{}
+
+	n, err := CopyRows(sink, NewReportStream(io.NopCloser(strings.NewReader(report))))
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, int64(3), sink.rows[2].ID)
+	assert.False(t, sink.closed)
+
+	sink = &testRowSink{err: fmt.Errorf("disk full")}
+	n, err = CopyRows(sink, NewReportStream(io.NopCloser(strings.NewReader(report))))
+	assert.Equal(t, fmt.Errorf("disk full"), err)
+	assert.Equal(t, 0, n)
+
+	_, err = CopyRows(&testRowSink{}, NewReportStream(io.NopCloser(strings.NewReader("id\nx\n"))))
+	assert.Equal(t, fmt.Errorf("orders report line 2: column id: invalid id %q", "x"), err)
+}
+
+func TestUpsertRows(t *testing.T) {
+	sink := &testRowSink{}
+	upsert := UpsertRows(sink)
+	changed, err := upsert.Upsert(ReportRow{ID: 1})
+	assert.Equal(t, nil, err)
+	assert.True(t, changed)
+	changed, err = upsert.Upsert(ReportRow{ID: 1})
+	assert.Equal(t, nil, err)
+	assert.True(t, changed)
+	assert.Equal(t, 2, len(sink.rows))
+	assert.False(t, sink.closed)
+}
+
+func TestRowSinkChain(t *testing.T) {
+	approved := &testRowSink{}
+	all := &testRowSink{}
+	mask := func(row ReportRow) (ReportRow, error) {
+		if row.ID == 4 {
+			return row, fmt.Errorf("unexpected order 4")
+		}
+		row.CustomerEmail = ""
+		return row, nil
+	}
+	sink := MultiSink(
+		FilterRows(TransformRows(approved, mask), func(row ReportRow) bool { return row.Status == StatusApproved }),
+		all,
+	)
+
+	assert.Equal(t, nil, sink.WriteRow(ReportRow{ID: 1, Status: StatusApproved, CustomerEmail: "a@b.c"}))
+	assert.Equal(t, nil, sink.WriteRow(ReportRow{ID: 2, Status: StatusDeclined, CustomerEmail: "a@b.c"}))
+	assert.Equal(t, nil, sink.WriteRow(ReportRow{ID: 3, Status: StatusApproved, CustomerEmail: "a@b.c"}))
+	assert.Equal(t, fmt.Errorf("unexpected order 4"), sink.WriteRow(ReportRow{ID: 4, Status: StatusApproved}))
+	assert.Equal(t, nil, sink.Close())
+
+	assert.Equal(t, []ReportRow{{ID: 1, Status: StatusApproved}, {ID: 3, Status: StatusApproved}}, approved.rows)
+	assert.Equal(t, 3, len(all.rows))
+	assert.True(t, approved.closed)
+	assert.True(t, all.closed)
+}
+
+func TestJSONLinesSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewJSONLinesSink(&buf)
+
+	assert.Equal(t, nil, sink.WriteRow(ReportRow{ID: 1, Status: StatusApproved, OrderAmount: MustParseMoney("1.5")}))
+	assert.Equal(t, nil, sink.WriteRow(ReportRow{ID: 2, Extras: map[string]string{"custom": "x"}}))
+	//buffered until Close
+	assert.Equal(t, 0, buf.Len())
+	assert.Equal(t, nil, sink.Close())
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	assert.Equal(t, 2, len(lines))
+	assert.True(t, strings.HasPrefix(lines[0], `{"id":1,"parent_id":0,"order_type":"","status":"APPROVED",`))
+	assert.True(t, strings.Contains(lines[0], `"order_amount":"1.50000000"`))
+	assert.True(t, strings.HasSuffix(lines[1], `"extras":{"custom":"x"}}`))
+}
@@ -0,0 +1,300 @@
+package zota
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+)
+
+// CheckpointStore persists the high-water marks of the ReportSyncers by key
+type CheckpointStore interface {
+	// Load returns the mark of key, false if none has been saved
+	Load(key string) (time.Time, bool, error)
+	// Save replaces the mark of key
+	Save(key string, mark time.Time) error
+}
+
+// MemoryCheckpointStore is a CheckpointStore keeping the marks in memory
+type MemoryCheckpointStore struct {
+	mu    sync.Mutex
+	marks map[string]time.Time
+}
+
+// NewMemoryCheckpointStore creates an empty MemoryCheckpointStore
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{marks: map[string]time.Time{}}
+}
+
+// Load implements CheckpointStore
+func (m *MemoryCheckpointStore) Load(key string) (time.Time, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	mark, ok := m.marks[key]
+	return mark, ok, nil
+}
+
+// Save implements CheckpointStore
+func (m *MemoryCheckpointStore) Save(key string, mark time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.marks[key] = mark
+	return nil
+}
+
+// FileCheckpointStore is a CheckpointStore keeping the marks in a json file
+// the file is replaced atomically on every Save
+type FileCheckpointStore struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+// Load implements CheckpointStore
+func (f *FileCheckpointStore) Load(key string) (time.Time, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	marks, err := f.load()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	mark, ok := marks[key]
+	return mark, ok, nil
+}
+
+// Save implements CheckpointStore
+func (f *FileCheckpointStore) Save(key string, mark time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	marks, err := f.load()
+	if err != nil {
+		return err
+	}
+	marks[key] = mark
+
+	b, err := json.Marshal(marks)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(f.Path, b)
+}
+
+// load reads the marks of the file, a missing file is empty
+func (f *FileCheckpointStore) load() (map[string]time.Time, error) {
+	marks := map[string]time.Time{}
+	b, err := os.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return marks, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(b, &marks)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected checkpoint store json:%v", err)
+	}
+	return marks, nil
+}
+
+// ReportSink receives the rows synced by a ReportSyncer
+// implemented by MemoryReportSink and SQLSink, UpsertRows adapts a RowSink
+type ReportSink interface {
+	// Upsert inserts or replaces the row with the same ID
+	// returns whether the row is new or different from the stored one
+	Upsert(row ReportRow) (bool, error)
+}
+
+// MemoryReportSink is a ReportSink keeping the rows in memory
+type MemoryReportSink struct {
+	mu   sync.Mutex
+	rows map[int64]ReportRow
+}
+
+// NewMemoryReportSink creates an empty MemoryReportSink
+func NewMemoryReportSink() *MemoryReportSink {
+	return &MemoryReportSink{rows: map[int64]ReportRow{}}
+}
+
+// Upsert implements ReportSink
+func (m *MemoryReportSink) Upsert(row ReportRow) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	old, ok := m.rows[row.ID]
+	if ok && reflect.DeepEqual(old, row) {
+		return false, nil
+	}
+	m.rows[row.ID] = row
+	return true, nil
+}
+
+// Get returns the row by order ID
+func (m *MemoryReportSink) Get(id int64) (ReportRow, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	row, ok := m.rows[id]
+	return row, ok
+}
+
+// Rows returns the rows sorted by order ID
+func (m *MemoryReportSink) Rows() []ReportRow {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	rows := make([]ReportRow, 0, len(m.rows))
+	for _, row := range m.rows {
+		rows = append(rows, row)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].ID < rows[j].ID })
+	return rows
+}
+
+// ReportSyncerConfig configures a ReportSyncer
+// zero values are replaced by the defaults
+type ReportSyncerConfig struct {
+	// Key is the key of the checkpoint, "orders-report" by default
+	Key string
+	// Query filters the synced orders, DateType, From and To are set by the syncer
+	Query OrdersReportQuery
+	// Start is the mark of the first sync when there is no checkpoint, 24 hours ago by default
+	Start time.Time
+	// Interval is the wait between two syncs, 1 minute by default
+	Interval time.Duration
+	// Overlap is synced again before the mark to catch late updates, 10 minutes by default
+	Overlap time.Duration
+	// Lookback is how long before the mark the orders created are fetched to find their updates, 7 days by default
+	// an order updated later than Lookback after its creation is missed
+	Lookback time.Duration
+	// Range configures the fetch of the report, see OrdersReportRange
+	Range ReportRangeOptions
+	// OnChange is called for every new or changed row
+	OnChange func(row ReportRow)
+	// OnError is called when a sync of Run fails, the next sync retries from the same mark
+	OnError func(err error)
+}
+
+// ReportSyncer keeps a copy of the Zota orders in a ReportSink by last update
+// the mark is the latest LastUpdateAt synced, the orders report can not be queried by last update,
+// so every sync fetches the orders created from the mark minus the overlap and the lookback up to now
+// and upserts the rows updated after the mark minus the overlap, the rows without LastUpdateAt are always upserted
+// a sync fetching more than MaxReportRange catches up over several syncs
+// the mark is not moved when a window of the report fails
+type ReportSyncer struct {
+	sdk   *SDK
+	store CheckpointStore
+	sink  ReportSink
+	cfg   ReportSyncerConfig
+}
+
+// NewReportSyncer creates a ReportSyncer
+// returns an error if the Lookback and the Overlap do not fit in MaxReportRange
+func NewReportSyncer(s *SDK, store CheckpointStore, sink ReportSink, cfg ReportSyncerConfig) (*ReportSyncer, error) {
+	if cfg.Key == "" {
+		cfg.Key = "orders-report"
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Minute
+	}
+	if cfg.Overlap <= 0 {
+		cfg.Overlap = 10 * time.Minute
+	}
+	if cfg.Lookback <= 0 {
+		cfg.Lookback = 7 * 24 * time.Hour
+	}
+	if cfg.Lookback+cfg.Overlap >= MaxReportRange {
+		return nil, fmt.Errorf("Lookback and Overlap must be shorter than %v", MaxReportRange)
+	}
+	cfg.Query.DateType = ReportDateCreated
+	return &ReportSyncer{sdk: s, store: store, sink: sink, cfg: cfg}, nil
+}
+
+// Mark returns the high-water mark, the zero time if there is no checkpoint
+func (r *ReportSyncer) Mark() (time.Time, error) {
+	mark, _, err := r.store.Load(r.cfg.Key)
+	return mark, err
+}
+
+// Run syncs every Interval until ctx is done
+// a failed sync is reported to OnError and retried at the next interval
+// returns ctx.Err()
+func (r *ReportSyncer) Run(ctx context.Context) error {
+	for {
+		err := r.SyncOnce(ctx)
+		if err != nil && ctx.Err() == nil && r.cfg.OnError != nil {
+			r.cfg.OnError(err)
+		}
+		if !sleep(ctx, r.cfg.Interval) {
+			return ctx.Err()
+		}
+	}
+}
+
+// SyncOnce fetches the orders updated since the mark into the sink and moves the mark
+// to the latest LastUpdateAt, or to the end of the fetched range when catching up
+func (r *ReportSyncer) SyncOnce(ctx context.Context) error {
+	mark, ok, err := r.store.Load(r.cfg.Key)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		mark = r.cfg.Start
+		if mark.IsZero() {
+			mark = timeNow().Add(-24 * time.Hour)
+		}
+	}
+
+	since := mark.Add(-r.cfg.Overlap)
+	from := since.Add(-r.cfg.Lookback).UTC().Truncate(time.Second)
+	to := timeNow().UTC().Truncate(time.Second)
+	catchUp := to.Sub(from) > MaxReportRange
+	if catchUp {
+		to = from.Add(MaxReportRange)
+	}
+	if !from.Before(to) {
+		return nil
+	}
+
+	q := r.cfg.Query
+	q.From, q.To = from, to
+	d, err := q.Build()
+	if err != nil {
+		return err
+	}
+	report, err := r.sdk.OrdersReportRange(withDefaultPriority(ctx, PriorityBatch), d, r.cfg.Range)
+	if err != nil {
+		return err
+	}
+	defer report.Close()
+
+	next := mark
+	for report.Next() {
+		row := report.Row()
+		if !row.LastUpdateAt.IsZero() && !row.LastUpdateAt.After(since) {
+			continue
+		}
+		changed, err := r.sink.Upsert(row)
+		if err != nil {
+			return err
+		}
+		if changed && r.cfg.OnChange != nil {
+			r.cfg.OnChange(row)
+		}
+		if row.LastUpdateAt.After(next) {
+			next = row.LastUpdateAt
+		}
+	}
+	err = report.Err()
+	if err != nil {
+		return err
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	//the orders created after the range are fetched by the next sync
+	if catchUp {
+		next = to
+	}
+	return r.store.Save(r.cfg.Key, next.UTC())
+}
@@ -0,0 +1,206 @@
+package zota
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportSyncer_SyncOnce(t *testing.T) {
+	now := time.Date(2020, 8, 1, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	client := &ClientMockReportRange{created: reportTimes(time.Hour, 11*time.Hour+51*time.Minute)}
+	store := NewMemoryCheckpointStore()
+	sink := NewMemoryReportSink()
+	var changed []int64
+	syncer, err := NewReportSyncer(testPollSDK(client), store, sink, ReportSyncerConfig{
+		Start:    time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC),
+		Lookback: 12 * time.Hour,
+		Range:    ReportRangeOptions{Window: MaxReportRange},
+		OnChange: func(row ReportRow) { changed = append(changed, row.ID) },
+	})
+	assert.Equal(t, nil, err)
+
+	err = syncer.SyncOnce(context.Background())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []int64{1, 2}, changed)
+	assert.Equal(t, []string{"2020-07-31 11:50:00/2020-08-01 12:00:00"}, client.Calls())
+	//the mark is the latest update
+	mark, err := syncer.Mark()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, time.Date(2020, 8, 1, 11, 51, 0, 0, time.UTC), mark)
+
+	//the overlap fetches the second order again, unchanged it is not an event
+	client.created = append(client.created, now.Add(30*time.Minute))
+	now = now.Add(time.Hour)
+	changed = nil
+	err = syncer.SyncOnce(context.Background())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []int64{3}, changed)
+	assert.Equal(t, "2020-07-31 23:41:00/2020-08-01 13:00:00", client.Calls()[1])
+	assert.Equal(t, 3, len(sink.Rows()))
+
+	row, ok := sink.Get(3)
+	assert.True(t, ok)
+	assert.Equal(t, "m-3", row.MerchantOrderID)
+
+	//an order created before the overlap is synced when it is updated
+	client.updated = map[int64]time.Time{1: now}
+	client.status = map[int64]OrderStatusCode{1: StatusApproved}
+	now = now.Add(30 * time.Minute)
+	changed = nil
+	err = syncer.SyncOnce(context.Background())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []int64{1}, changed)
+	row, _ = sink.Get(1)
+	assert.Equal(t, StatusApproved, row.Status)
+	mark, _ = syncer.Mark()
+	assert.Equal(t, time.Date(2020, 8, 1, 13, 0, 0, 0, time.UTC), mark)
+}
+
+func TestReportSyncer_SyncOnceFailure(t *testing.T) {
+	now := time.Date(2020, 8, 1, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	client := &ClientMockReportRange{
+		created: reportTimes(time.Hour),
+		fail:    map[string]bool{"2020-07-31 11:50:00": true},
+	}
+	store := NewMemoryCheckpointStore()
+	syncer, err := NewReportSyncer(testPollSDK(client), store, NewMemoryReportSink(), ReportSyncerConfig{
+		Start:    time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC),
+		Lookback: 12 * time.Hour,
+		Range:    ReportRangeOptions{Window: MaxReportRange},
+	})
+	assert.Equal(t, nil, err)
+
+	err = syncer.SyncOnce(context.Background())
+	assert.Equal(t, "window 2020-07-31 11:50:00/2020-08-01 12:00:00: orders report error code:400 message:bad request", err.Error())
+	_, ok, _ := store.Load("orders-report")
+	assert.False(t, ok)
+
+	//the next sync retries from the same mark
+	client.fail = nil
+	now = now.Add(time.Minute)
+	err = syncer.SyncOnce(context.Background())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "2020-07-31 11:50:00/2020-08-01 12:01:00", client.Calls()[1])
+}
+
+func TestReportSyncer_SyncOnceCatchUp(t *testing.T) {
+	now := time.Date(2020, 10, 1, 0, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	store := NewMemoryCheckpointStore()
+	err := store.Save("orders", time.Date(2020, 8, 8, 0, 10, 0, 0, time.UTC))
+	assert.Equal(t, nil, err)
+
+	client := &ClientMockReportRange{}
+	syncer, err := NewReportSyncer(testPollSDK(client), store, NewMemoryReportSink(), ReportSyncerConfig{
+		Key:   "orders",
+		Range: ReportRangeOptions{Window: MaxReportRange},
+	})
+	assert.Equal(t, nil, err)
+	err = syncer.SyncOnce(context.Background())
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []string{"2020-08-01/2020-08-31"}, client.Calls())
+
+	//a sync fetches at most MaxReportRange, the mark moves to the end of the range
+	mark, err := syncer.Mark()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, time.Date(2020, 9, 1, 0, 0, 0, 0, time.UTC), mark)
+}
+
+func TestReportSyncer_Run(t *testing.T) {
+	timeNow = func() time.Time { return time.Date(2020, 8, 1, 12, 0, 0, 0, time.UTC) }
+	defer func() { timeNow = time.Now }()
+
+	//the first sync fails
+	client := &ClientMockReportRange{
+		created: reportTimes(time.Hour),
+		fail:    map[string]bool{"2020-07-31 11:50:00": true},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var errs []error
+	syncer, err := NewReportSyncer(testPollSDK(client), NewMemoryCheckpointStore(), NewMemoryReportSink(), ReportSyncerConfig{
+		Start:    time.Date(2020, 8, 1, 0, 0, 0, 0, time.UTC),
+		Interval: time.Millisecond,
+		Lookback: 12 * time.Hour,
+		Range:    ReportRangeOptions{Window: MaxReportRange},
+		OnChange: func(row ReportRow) { cancel() },
+		OnError: func(err error) {
+			errs = append(errs, err)
+			client.fail = nil
+		},
+	})
+	assert.Equal(t, nil, err)
+
+	err = syncer.Run(ctx)
+	assert.Equal(t, context.Canceled, err)
+	assert.Equal(t, 1, len(errs))
+	assert.Equal(t, 2, len(client.Calls()))
+}
+
+func TestNewReportSyncer(t *testing.T) {
+	//the orders created since the mark minus the overlap and the lookback must fit in one report
+	_, err := NewReportSyncer(testPollSDK(nil), NewMemoryCheckpointStore(), NewMemoryReportSink(), ReportSyncerConfig{Lookback: 31 * 24 * time.Hour})
+	assert.Equal(t, fmt.Errorf("Lookback and Overlap must be shorter than 744h0m0s"), err)
+
+	//the orders are fetched by creation date
+	syncer, err := NewReportSyncer(testPollSDK(nil), NewMemoryCheckpointStore(), NewMemoryReportSink(), ReportSyncerConfig{Query: OrdersReportQuery{DateType: ReportDateEnded}})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, ReportDateCreated, syncer.cfg.Query.DateType)
+	assert.Equal(t, 7*24*time.Hour, syncer.cfg.Lookback)
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoints.json")
+	store := &FileCheckpointStore{Path: path}
+
+	_, ok, err := store.Load("orders")
+	assert.Equal(t, nil, err)
+	assert.False(t, ok)
+
+	mark := time.Date(2020, 8, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, nil, store.Save("orders", mark))
+	assert.Equal(t, nil, store.Save("payouts", mark.Add(time.Hour)))
+
+	loaded, ok, err := (&FileCheckpointStore{Path: path}).Load("orders")
+	assert.Equal(t, nil, err)
+	assert.True(t, ok)
+	assert.True(t, mark.Equal(loaded))
+
+	assert.Equal(t, nil, os.WriteFile(path, []byte("{"), 0600))
+	_, _, err = store.Load("orders")
+	assert.Equal(t, fmt.Errorf("unexpected checkpoint store json:unexpected end of JSON input"), err)
+}
+
+func TestMemoryReportSink(t *testing.T) {
+	sink := NewMemoryReportSink()
+
+	changed, err := sink.Upsert(ReportRow{ID: 2, Status: StatusPending})
+	assert.Equal(t, nil, err)
+	assert.True(t, changed)
+
+	changed, _ = sink.Upsert(ReportRow{ID: 1, Status: StatusApproved})
+	assert.True(t, changed)
+
+	changed, _ = sink.Upsert(ReportRow{ID: 2, Status: StatusPending})
+	assert.False(t, changed)
+
+	changed, _ = sink.Upsert(ReportRow{ID: 2, Status: StatusApproved})
+	assert.True(t, changed)
+
+	assert.Equal(t, []ReportRow{{ID: 1, Status: StatusApproved}, {ID: 2, Status: StatusApproved}}, sink.Rows())
+}
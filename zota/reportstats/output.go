@@ -0,0 +1,106 @@
+package reportstats
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/zota/go-sdk/zota"
+)
+
+// WriteCSV writes a line per group with a column per dimension
+// the median duration is in seconds, the top errors are "message (count)" separated by "; "
+// the amounts of a group with several currencies are empty
+func (s *Stats) WriteCSV(w io.Writer) error {
+	c := csv.NewWriter(w)
+
+	header := make([]string, 0, len(s.Dimensions)+8)
+	for _, d := range s.Dimensions {
+		header = append(header, string(d))
+	}
+	header = append(header, "count", "final", "approved", "approval_rate", "amount", "approved_amount", "median_duration_seconds", "top_errors")
+	err := c.Write(header)
+	if err != nil {
+		return err
+	}
+
+	for _, g := range s.Groups {
+		amount, approvedAmount := formatAmounts(g, zota.Money.String)
+		line := append([]string(nil), g.Key...)
+		line = append(line,
+			strconv.Itoa(g.Count),
+			strconv.Itoa(g.Final),
+			strconv.Itoa(g.Approved),
+			strconv.FormatFloat(g.ApprovalRate, 'f', 4, 64),
+			amount,
+			approvedAmount,
+			strconv.FormatFloat(g.MedianDuration.Seconds(), 'f', -1, 64),
+			formatErrors(g.TopErrors),
+		)
+		err = c.Write(line)
+		if err != nil {
+			return err
+		}
+	}
+	c.Flush()
+	return c.Error()
+}
+
+// WriteTable writes the groups and the total as a plain-text table
+// amounts with 2 decimal places and the approval rate as a percentage
+// the amounts of a group or a total with several currencies are empty
+func (s *Stats) WriteTable(w io.Writer) error {
+	t := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	header := make([]string, 0, len(s.Dimensions)+8)
+	for _, d := range s.Dimensions {
+		header = append(header, strings.ToUpper(strings.ReplaceAll(string(d), "_", " ")))
+	}
+	header = append(header, "COUNT", "FINAL", "APPROVED", "APPROVAL RATE", "AMOUNT", "APPROVED AMOUNT", "MEDIAN DURATION", "TOP ERRORS")
+	fmt.Fprintln(t, strings.Join(header, "\t"))
+
+	//without dimensions the only group is the total
+	groups := []Group{s.Total}
+	if len(s.Dimensions) > 0 {
+		total := s.Total
+		total.Key = make([]string, len(s.Dimensions))
+		total.Key[0] = "TOTAL"
+		groups = append(append([]Group(nil), s.Groups...), total)
+	}
+	for _, g := range groups {
+		amount, approvedAmount := formatAmounts(g, func(m zota.Money) string { return m.Format(2) })
+		line := append([]string(nil), g.Key...)
+		line = append(line,
+			strconv.Itoa(g.Count),
+			strconv.Itoa(g.Final),
+			strconv.Itoa(g.Approved),
+			fmt.Sprintf("%.1f%%", g.ApprovalRate*100),
+			amount,
+			approvedAmount,
+			g.MedianDuration.String(),
+			formatErrors(g.TopErrors),
+		)
+		fmt.Fprintln(t, strings.Join(line, "\t"))
+	}
+	return t.Flush()
+}
+
+// formatAmounts formats the amounts of the group, empty if the orders have several currencies
+func formatAmounts(g Group, format func(m zota.Money) string) (string, string) {
+	if g.MixedCurrencies {
+		return "", ""
+	}
+	return format(g.Amount), format(g.ApprovedAmount)
+}
+
+// formatErrors formats the top errors as "message (count)" separated by "; "
+func formatErrors(errors []ErrorCount) string {
+	parts := make([]string, len(errors))
+	for i, e := range errors {
+		parts[i] = fmt.Sprintf("%v (%v)", e.Message, e.Count)
+	}
+	return strings.Join(parts, "; ")
+}
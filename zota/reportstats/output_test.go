@@ -0,0 +1,73 @@
+package reportstats
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zota/go-sdk/zota"
+)
+
+func TestStats_WriteCSV(t *testing.T) {
+	stats, err := Aggregate(testRows(), Options{Dimensions: []Dimension{OrderType, Currency}})
+	assert.Equal(t, nil, err)
+
+	var buf bytes.Buffer
+	assert.Equal(t, nil, stats.WriteCSV(&buf))
+	assert.Equal(t, ""+
+		"order_type,currency,count,final,approved,approval_rate,amount,approved_amount,median_duration_seconds,top_errors\n"+
+		"PAYOUT,THB,3,3,0,0.0000,1700.00000000,0.00000000,1800,bank timeout (1); insufficient funds (1); invalid account (1)\n"+
+		"SALE,MYR,4,3,2,0.6667,185.50000000,125.50000000,180,insufficient funds (1)\n",
+		buf.String())
+
+	//the declined orders have several currencies, their amounts are empty
+	stats, err = Aggregate(testRows(), Options{Dimensions: []Dimension{Status}, TopErrors: 1})
+	assert.Equal(t, nil, err)
+	buf.Reset()
+	assert.Equal(t, nil, stats.WriteCSV(&buf))
+	assert.Equal(t, ""+
+		"status,count,final,approved,approval_rate,amount,approved_amount,median_duration_seconds,top_errors\n"+
+		"APPROVED,2,2,2,1.0000,125.50000000,125.50000000,120,\n"+
+		"DECLINED,3,3,0,0.0000,,,600,insufficient funds (2)\n"+
+		"ERROR,1,1,0,0.0000,500.00000000,0.00000000,1800,bank timeout (1)\n"+
+		"PENDING,1,0,0,0.0000,10.00000000,0.00000000,0,\n",
+		buf.String())
+}
+
+func TestStats_WriteTable(t *testing.T) {
+	stats, err := Aggregate(testRows(), Options{Dimensions: []Dimension{Endpoint}})
+	assert.Equal(t, nil, err)
+
+	var buf bytes.Buffer
+	assert.Equal(t, nil, stats.WriteTable(&buf))
+	assert.Equal(t, ""+
+		"ENDPOINT  COUNT  FINAL  APPROVED  APPROVAL RATE  AMOUNT   APPROVED AMOUNT  MEDIAN DURATION  TOP ERRORS\n"+
+		"503368    4      3      2         66.7%          185.50   125.50           3m0s             insufficient funds (1)\n"+
+		"503369    3      3      0         0.0%           1700.00  0.00             30m0s            bank timeout (1); insufficient funds (1); invalid account (1)\n"+
+		"TOTAL     7      6      2         33.3%                                    7m30s            insufficient funds (2); bank timeout (1); invalid account (1)\n",
+		buf.String())
+
+	//the total of a single currency has the amounts
+	a, err := NewAggregator(Options{Dimensions: []Dimension{Status}})
+	assert.Equal(t, nil, err)
+	a.Add(zota.ReportRow{Status: zota.StatusApproved, OrderCurrency: "MYR", OrderAmount: zota.MustParseMoney("10")})
+	a.Add(zota.ReportRow{Status: zota.StatusDeclined, OrderCurrency: "MYR", OrderAmount: zota.MustParseMoney("5")})
+	buf.Reset()
+	assert.Equal(t, nil, a.Stats().WriteTable(&buf))
+	assert.Equal(t, ""+
+		"STATUS    COUNT  FINAL  APPROVED  APPROVAL RATE  AMOUNT  APPROVED AMOUNT  MEDIAN DURATION  TOP ERRORS\n"+
+		"APPROVED  1      1      1         100.0%         10.00   10.00            0s               \n"+
+		"DECLINED  1      1      0         0.0%           5.00    0.00             0s               \n"+
+		"TOTAL     2      2      1         50.0%          15.00   10.00            0s               \n",
+		buf.String())
+
+	//without dimensions only the total is written
+	stats, err = Aggregate(testRows(), Options{TopErrors: 1})
+	assert.Equal(t, nil, err)
+	buf.Reset()
+	assert.Equal(t, nil, stats.WriteTable(&buf))
+	assert.Equal(t, ""+
+		"COUNT  FINAL  APPROVED  APPROVAL RATE  AMOUNT  APPROVED AMOUNT  MEDIAN DURATION  TOP ERRORS\n"+
+		"7      6      2         33.3%                                   7m30s            insufficient funds (2)\n",
+		buf.String())
+}
@@ -0,0 +1,272 @@
+// Package reportstats aggregates the rows of the Zota orders report
+// by configurable dimensions, e.g. the volume by currency,
+// the approval rate per endpoint or the top decline reasons
+package reportstats
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/zota/go-sdk/zota"
+)
+
+// Dimension is a property the rows are grouped by
+type Dimension string
+
+const (
+	OrderType     Dimension = "order_type"
+	Status        Dimension = "status"
+	Endpoint      Dimension = "endpoint"
+	Currency      Dimension = "currency"
+	Country       Dimension = "country"
+	PaymentMethod Dimension = "payment_method"
+	// Bank is the selected bank of a deposit or the customer bank of a payout
+	Bank Dimension = "bank"
+	// Hour is the hour of creation, e.g. "2020-08-05 13:00"
+	Hour Dimension = "hour"
+	// Day is the day of creation, e.g. "2020-08-05"
+	Day Dimension = "day"
+)
+
+// value returns the value of the dimension for the row, times in loc
+func (d Dimension) value(row zota.ReportRow, loc *time.Location) string {
+	switch d {
+	case OrderType:
+		return row.OrderType
+	case Status:
+		return string(row.Status)
+	case Endpoint:
+		return row.EndpointID
+	case Currency:
+		return row.OrderCurrency
+	case Country:
+		return row.CustomerCountryCode
+	case PaymentMethod:
+		return row.PaymentMethodID
+	case Bank:
+		if row.SelectedBankCode != "" {
+			return row.SelectedBankCode
+		}
+		return row.CustomerBankCode
+	case Hour:
+		return row.CreatedAt.In(loc).Format("2006-01-02 15:00")
+	case Day:
+		return row.CreatedAt.In(loc).Format("2006-01-02")
+	}
+	return ""
+}
+
+// Options configures an Aggregator
+// zero values are replaced by the defaults
+type Options struct {
+	// Dimensions the rows are grouped by, in order, all the rows are one group if none
+	// the amounts of a group with several currencies are not summed, use the Currency dimension to sum per currency
+	Dimensions []Dimension
+	// TopErrors is the number of client error messages of a group, 5 by default
+	TopErrors int
+	// Location of the Hour and Day dimensions, UTC by default
+	Location *time.Location
+}
+
+// ErrorCount is the number of orders of a client error message
+type ErrorCount struct {
+	Message string `json:"message"`
+	Count   int    `json:"count"`
+}
+
+// Group is the aggregate of the rows with the same dimension values
+type Group struct {
+	// Key has the values of the dimensions, in the order of Options.Dimensions
+	Key []string `json:"key"`
+	// Count is the number of orders
+	Count int `json:"count"`
+	// Final is the number of orders with a final status
+	Final int `json:"final"`
+	// Approved is the number of approved orders
+	Approved int `json:"approved"`
+	// ApprovalRate is Approved / Final, 0 without final orders
+	ApprovalRate float64 `json:"approvalRate"`
+	// Amount is the sum of the amounts of the orders, zero if MixedCurrencies
+	Amount zota.Money `json:"amount"`
+	// ApprovedAmount is the sum of the amounts of the approved orders, zero if MixedCurrencies
+	ApprovedAmount zota.Money `json:"approvedAmount"`
+	// MixedCurrencies reports whether the orders have several currencies,
+	// their amounts cannot be summed
+	MixedCurrencies bool `json:"mixedCurrencies"`
+	// MedianDuration is the median time from created_at to ended_at of the ended orders
+	MedianDuration time.Duration `json:"medianDuration"`
+	// TopErrors are the most frequent client error messages, by descending count
+	TopErrors []ErrorCount `json:"topErrors"`
+}
+
+// Stats are the groups of an Aggregator
+type Stats struct {
+	Dimensions []Dimension `json:"dimensions"`
+	// Groups are sorted by key
+	Groups []Group `json:"groups"`
+	// Total aggregates all the rows
+	Total Group `json:"total"`
+}
+
+// Aggregator aggregates report rows into groups
+type Aggregator struct {
+	opts   Options
+	groups map[string]*group
+	total  *group
+}
+
+// group is a Group being aggregated
+type group struct {
+	Group
+	durations []time.Duration
+	errors    map[string]int
+	currency  string
+}
+
+// NewAggregator creates an Aggregator
+// returns an error if a dimension is unknown
+func NewAggregator(opts Options) (*Aggregator, error) {
+	for _, d := range opts.Dimensions {
+		switch d {
+		case OrderType, Status, Endpoint, Currency, Country, PaymentMethod, Bank, Hour, Day:
+		default:
+			return nil, fmt.Errorf("unknown dimension %q", d)
+		}
+	}
+	if opts.TopErrors <= 0 {
+		opts.TopErrors = 5
+	}
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+	return &Aggregator{opts: opts, groups: map[string]*group{}, total: newGroup(nil)}, nil
+}
+
+// Aggregate aggregates the rows, e.g. of a zota.ReportStream
+func Aggregate(rows zota.RowIterator, opts Options) (*Stats, error) {
+	a, err := NewAggregator(opts)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		a.Add(rows.Row())
+	}
+	if rows.Err() != nil {
+		return nil, rows.Err()
+	}
+	return a.Stats(), nil
+}
+
+// Add adds a row to its group
+func (a *Aggregator) Add(row zota.ReportRow) {
+	key := make([]string, len(a.opts.Dimensions))
+	for i, d := range a.opts.Dimensions {
+		key[i] = d.value(row, a.opts.Location)
+	}
+	id := strings.Join(key, "\x00")
+	g, ok := a.groups[id]
+	if !ok {
+		g = newGroup(key)
+		a.groups[id] = g
+	}
+	g.add(row)
+	a.total.add(row)
+}
+
+// Stats returns the aggregates of the rows added so far
+func (a *Aggregator) Stats() *Stats {
+	stats := &Stats{
+		Dimensions: a.opts.Dimensions,
+		Groups:     make([]Group, 0, len(a.groups)),
+		Total:      a.total.result(a.opts.TopErrors),
+	}
+	for _, g := range a.groups {
+		stats.Groups = append(stats.Groups, g.result(a.opts.TopErrors))
+	}
+	sort.Slice(stats.Groups, func(i, j int) bool {
+		return lessKey(stats.Groups[i].Key, stats.Groups[j].Key)
+	})
+	return stats
+}
+
+// newGroup creates an empty group
+func newGroup(key []string) *group {
+	return &group{Group: Group{Key: key}, errors: map[string]int{}}
+}
+
+// add adds a row to the group
+func (g *group) add(row zota.ReportRow) {
+	g.Count++
+	if g.Count == 1 {
+		g.currency = row.OrderCurrency
+	} else if row.OrderCurrency != g.currency {
+		g.MixedCurrencies = true
+	}
+	g.Amount = g.Amount.Add(row.OrderAmount)
+	if row.Status.IsFinal() {
+		g.Final++
+	}
+	if row.Status.IsSuccess() {
+		g.Approved++
+		g.ApprovedAmount = g.ApprovedAmount.Add(row.OrderAmount)
+	}
+	if !row.CreatedAt.IsZero() && !row.EndedAt.IsZero() {
+		g.durations = append(g.durations, row.EndedAt.Sub(row.CreatedAt))
+	}
+	if row.ClientErrorMessage != "" {
+		g.errors[row.ClientErrorMessage]++
+	}
+}
+
+// result computes the Group
+func (g *group) result(topErrors int) Group {
+	res := g.Group
+	if res.Final > 0 {
+		res.ApprovalRate = float64(res.Approved) / float64(res.Final)
+	}
+	res.MedianDuration = median(g.durations)
+	if res.MixedCurrencies {
+		res.Amount, res.ApprovedAmount = zota.Money{}, zota.Money{}
+	}
+
+	res.TopErrors = make([]ErrorCount, 0, len(g.errors))
+	for message, count := range g.errors {
+		res.TopErrors = append(res.TopErrors, ErrorCount{Message: message, Count: count})
+	}
+	sort.Slice(res.TopErrors, func(i, j int) bool {
+		if res.TopErrors[i].Count != res.TopErrors[j].Count {
+			return res.TopErrors[i].Count > res.TopErrors[j].Count
+		}
+		return res.TopErrors[i].Message < res.TopErrors[j].Message
+	})
+	if len(res.TopErrors) > topErrors {
+		res.TopErrors = res.TopErrors[:topErrors]
+	}
+	return res
+}
+
+// median returns the median of the durations, 0 if none
+func median(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), durations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		return sorted[mid]
+	}
+	return (sorted[mid-1] + sorted[mid]) / 2
+}
+
+// lessKey compares two keys value by value
+func lessKey(a []string, b []string) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
@@ -0,0 +1,111 @@
+package reportstats
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zota/go-sdk/zota"
+)
+
+// testReport is a Zota orders report
+const testReport = `id,order_type,status,endpoint_id,order_currency,order_amount,customer_country_code,payment_method_id,selected_bank_code,customer_bank_code,client_error_message,created_at,ended_at
+1,SALE,APPROVED,503368,MYR,100.00000000,MY,BANK,MBB,,,2020-08-05 13:00:00 +0000 UTC,2020-08-05 13:01:00 +0000 UTC
+2,SALE,DECLINED,503368,MYR,50.00000000,MY,BANK,MBB,,insufficient funds,2020-08-05 13:10:00 +0000 UTC,2020-08-05 13:15:00 +0000 UTC
+3,SALE,APPROVED,503368,MYR,25.50000000,MY,BANK,CIMB,,,2020-08-05 14:00:00 +0000 UTC,2020-08-05 14:03:00 +0000 UTC
+4,SALE,PENDING,503368,MYR,10.00000000,MY,BANK,CIMB,,,2020-08-05 14:30:00 +0000 UTC,
+5,PAYOUT,DECLINED,503369,THB,1000.00000000,TH,PAYOUT,,BBL,insufficient funds,2020-08-06 01:00:00 +0000 UTC,2020-08-06 02:00:00 +0000 UTC
+6,PAYOUT,ERROR,503369,THB,500.00000000,TH,PAYOUT,,BBL,bank timeout,2020-08-06 03:00:00 +0000 UTC,2020-08-06 03:30:00 +0000 UTC
+7,PAYOUT,DECLINED,503369,THB,200.00000000,TH,PAYOUT,,KBANK,invalid account,2020-08-06 04:00:00 +0000 UTC,2020-08-06 04:10:00 +0000 UTC
+`
+
+func testRows() zota.RowIterator {
+	return zota.NewReportStream(io.NopCloser(strings.NewReader(testReport)))
+}
+
+func TestAggregate(t *testing.T) {
+	stats, err := Aggregate(testRows(), Options{Dimensions: []Dimension{OrderType, Currency}})
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, []Group{
+		{
+			Key:            []string{"PAYOUT", "THB"},
+			Count:          3,
+			Final:          3,
+			Approved:       0,
+			Amount:         zota.MustParseMoney("1700"),
+			MedianDuration: 30 * time.Minute,
+			TopErrors:      []ErrorCount{{"bank timeout", 1}, {"insufficient funds", 1}, {"invalid account", 1}},
+		},
+		{
+			Key:            []string{"SALE", "MYR"},
+			Count:          4,
+			Final:          3,
+			Approved:       2,
+			ApprovalRate:   2.0 / 3,
+			Amount:         zota.MustParseMoney("185.5"),
+			ApprovedAmount: zota.MustParseMoney("125.5"),
+			MedianDuration: 3 * time.Minute,
+			TopErrors:      []ErrorCount{{"insufficient funds", 1}},
+		},
+	}, stats.Groups)
+
+	assert.Equal(t, 7, stats.Total.Count)
+	assert.True(t, stats.Total.MixedCurrencies)
+	assert.Equal(t, zota.Money{}, stats.Total.Amount)
+	assert.Equal(t, 2.0/6, stats.Total.ApprovalRate)
+	//median of 1m, 3m, 5m, 10m, 30m, 60m
+	assert.Equal(t, 7*time.Minute+30*time.Second, stats.Total.MedianDuration)
+	assert.Equal(t, []ErrorCount{{"insufficient funds", 2}, {"bank timeout", 1}, {"invalid account", 1}}, stats.Total.TopErrors)
+}
+
+func TestAggregateDimensions(t *testing.T) {
+	keys := func(opts Options) [][]string {
+		stats, err := Aggregate(testRows(), opts)
+		assert.Equal(t, nil, err)
+		var keys [][]string
+		for _, g := range stats.Groups {
+			keys = append(keys, g.Key)
+		}
+		return keys
+	}
+
+	assert.Equal(t, [][]string{{"APPROVED"}, {"DECLINED"}, {"ERROR"}, {"PENDING"}}, keys(Options{Dimensions: []Dimension{Status}}))
+	assert.Equal(t, [][]string{{"503368", "MY"}, {"503369", "TH"}}, keys(Options{Dimensions: []Dimension{Endpoint, Country}}))
+	assert.Equal(t, [][]string{{"BANK", "CIMB"}, {"BANK", "MBB"}, {"PAYOUT", "BBL"}, {"PAYOUT", "KBANK"}}, keys(Options{Dimensions: []Dimension{PaymentMethod, Bank}}))
+	assert.Equal(t, [][]string{{"2020-08-05"}, {"2020-08-06"}}, keys(Options{Dimensions: []Dimension{Day}}))
+	assert.Equal(t, [][]string{{"2020-08-05 13:00"}, {"2020-08-05 14:00"}, {"2020-08-06 01:00"}, {"2020-08-06 03:00"}, {"2020-08-06 04:00"}}, keys(Options{Dimensions: []Dimension{Hour}}))
+	assert.Equal(t, [][]string{{"2020-08-05"}, {"2020-08-06"}}, keys(Options{Dimensions: []Dimension{Day}, Location: time.FixedZone("UTC+8", 8*60*60)}))
+	assert.Equal(t, [][]string{{"2020-08-05"}, {"2020-08-06"}}, keys(Options{Dimensions: []Dimension{Day}, Location: time.FixedZone("UTC-2", -2*60*60)}))
+	assert.Equal(t, [][]string{{}}, keys(Options{}))
+}
+
+func TestAggregateOptions(t *testing.T) {
+	stats, err := Aggregate(testRows(), Options{TopErrors: 1})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, []ErrorCount{{"insufficient funds", 2}}, stats.Total.TopErrors)
+
+	_, err = Aggregate(testRows(), Options{Dimensions: []Dimension{"merchant"}})
+	assert.Equal(t, fmt.Errorf("unknown dimension %q", "merchant"), err)
+
+	_, err = Aggregate(zota.NewReportStream(io.NopCloser(strings.NewReader("id\nx\n"))), Options{})
+	assert.Equal(t, fmt.Errorf("orders report line 2: column id: invalid id %q", "x"), err)
+}
+
+func TestAggregator(t *testing.T) {
+	a, err := NewAggregator(Options{Dimensions: []Dimension{Status}})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(a.Stats().Groups))
+
+	a.Add(zota.ReportRow{ID: 1, Status: zota.StatusApproved, OrderAmount: zota.MustParseMoney("1")})
+	a.Add(zota.ReportRow{ID: 2, Status: zota.StatusApproved, OrderAmount: zota.MustParseMoney("2")})
+	stats := a.Stats()
+	assert.Equal(t, 1, len(stats.Groups))
+	assert.Equal(t, zota.MustParseMoney("3"), stats.Groups[0].ApprovedAmount)
+	assert.Equal(t, 1.0, stats.Groups[0].ApprovalRate)
+	assert.Equal(t, time.Duration(0), stats.Groups[0].MedianDuration)
+	assert.Equal(t, []ErrorCount{}, stats.Groups[0].TopErrors)
+}
@@ -2,13 +2,13 @@ package zota
 
 import (
 	"bytes"
+	"context"
 	"crypto/sha256"
 	"crypto/tls"
 	"encoding/hex"
 	"fmt"
-	"io/ioutil"
+	"io"
 	"net/http"
-	"runtime"
 	"time"
 )
 
@@ -21,15 +21,44 @@ const (
 	LIVE string = "https://api.zotapay.com"
 )
 
+// Operation represents a Zota API operation
+type Operation string
+
+const (
+	OperationDeposit      Operation = "deposit"
+	OperationPayout       Operation = "payout"
+	OperationOrderStatus  Operation = "order-status"
+	OperationOrdersReport Operation = "orders-report"
+)
+
 // SDK represents the base SDK structure
-// all properties are required, except HttpClient
+// all properties are required, except HttpClient, PayoutRules, PayoutProfiles, BankCatalog, EndpointRouter, RateLimiter, CircuitBreaker, Transport, DryRun and OnDryRun
+// EndpointID is optional if EndpointRouter is set, it is used when the router returns no endpoint
 // HttpClient implement httpClient interface if is empty will be initialized
+// PayoutRules are additional validations applied on every payout
+// PayoutProfiles are the country specific payout profiles, DefaultPayoutProfiles if nil
+// BankCatalog is used to validate CustomerBankCode, DefaultBankCatalog if nil
+// EndpointRouter selects the EndpointID of every deposit and payout order
+// RateLimiter limits the requests per operation and EndpointID, not limited if nil
+// CircuitBreaker fails the requests fast while the Zota API is down, disabled if nil
+// Transport configures the timeouts and the http client created when HttpClient is empty
+// DryRun skips the requests, overridable per call with WithDryRun
+// OnDryRun receives the requests skipped by DryRun without their body, logged with the log package if nil
 type SDK struct {
 	MerchantID        string
 	MerchantSecretKey string
 	EndpointID        string
 	ApiBaseURL        string
 	HttpClient        httpClient
+	PayoutRules       []PayoutRule
+	PayoutProfiles    []PayoutProfile
+	BankCatalog       *BankCatalog
+	EndpointRouter    EndpointRouter
+	RateLimiter       *RateLimiter
+	CircuitBreaker    *CircuitBreaker
+	Transport         *TransportConfig
+	DryRun            DryRunMode
+	OnDryRun          func(r PreparedRequest)
 }
 
 // httpClient is the interface that wraps the basic http.Client Do method.
@@ -46,7 +75,7 @@ func (s *SDK) validate() error {
 	if s.MerchantSecretKey == "" {
 		return fmt.Errorf("MerchantSecretKey is required")
 	}
-	if s.EndpointID == "" {
+	if s.EndpointID == "" && s.EndpointRouter == nil {
 		return fmt.Errorf("EndpointID is required")
 	}
 	if s.ApiBaseURL == "" {
@@ -60,8 +89,19 @@ func (s *SDK) validate() error {
 
 // initHttpClient is checking if *SDK.HttpClient has been populated
 // in case it has not, crete new one.
-func (s *SDK) initHttpClient() {
+// the client is created from the Transport config if set
+// not safe for concurrent use, the concurrent helpers call it before starting their goroutines
+func (s *SDK) initHttpClient() error {
 	if s.HttpClient == nil {
+		if s.Transport != nil {
+			client, err := s.Transport.HttpClient()
+			if err != nil {
+				return err
+			}
+			s.HttpClient = client
+			return nil
+		}
+
 		//enforce tls min version > 1.2
 		mTLSConfig := &tls.Config{}
 		// This is deprecated: https://pkg.go.dev/crypto/tls#Config.PreferServerCipherSuites
@@ -76,37 +116,86 @@ func (s *SDK) initHttpClient() {
 			Transport: tr,
 		}
 	}
+	return nil
 }
 
-// postJson makes an http-post request to a Zota API endpoint.
-// returns the response as []byte, or an error.
-func (s *SDK) httpDo(method string, url string, data []byte) (code int, body []byte, err error) {
-
-	s.initHttpClient()
+// httpStream makes an http request to a Zota API endpoint
+// and returns the response with the body not read, the body must be closed.
+// the request is cancelled when ctx is done.
+// the request goes through the circuit breaker of the operation
+// and is limited by the timeout of the operation, including the body read.
+func (s *SDK) httpStream(ctx context.Context, r PreparedRequest) (resp *http.Response, err error) {
 
-	req, err := http.NewRequest(method, url, bytes.NewBuffer(data))
+	err = s.initHttpClient()
 	if err != nil {
 		return
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("User-Agent", fmt.Sprintf("Zota Go SDK %v(%v; %v; %v)", VERSION, runtime.GOOS, runtime.GOARCH, runtime.Version()))
 
-	resp, err := s.HttpClient.Do(req)
-	if resp != nil {
-		defer resp.Body.Close()
+	//the timeout of the operation is a failure for the circuit breaker,
+	//unlike the cancellation of ctx
+	reqCtx, cancel := ctx, context.CancelFunc(func() {})
+	if timeout := s.timeout(r.Operation); timeout > 0 {
+		reqCtx, cancel = context.WithTimeout(ctx, timeout)
 	}
+
+	req, err := http.NewRequestWithContext(reqCtx, r.Method, r.URL, bytes.NewBuffer(r.Body))
 	if err != nil {
+		cancel()
 		return
 	}
+	for k, v := range r.Headers {
+		req.Header.Set(k, v)
+	}
 
-	code = resp.StatusCode
-	body, err = ioutil.ReadAll(resp.Body)
+	//fail fast while the circuit of the operation is open
+	done, err := s.CircuitBreaker.allow(r.Operation)
 	if err != nil {
+		cancel()
 		return
 	}
+
+	if sent, ok := ctx.Value(sentHookKey{}).(func()); ok {
+		sent()
+	}
+	resp, err = s.HttpClient.Do(req)
+	if err != nil {
+		if resp != nil {
+			resp.Body.Close()
+		}
+		done(ctx, 0, err)
+		cancel()
+		return nil, err
+	}
+	done(ctx, resp.StatusCode, nil)
+
+	//release the timeout once the body is closed
+	resp.Body = cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
 	return
 }
 
+// sentHookKey is the context key of the hook called
+// right before a request is handed to the HttpClient
+type sentHookKey struct{}
+
+// withSentHook returns a context whose requests call sent once they are sent,
+// the errors of a request without the call were returned before sending it
+func withSentHook(ctx context.Context, sent func()) context.Context {
+	return context.WithValue(ctx, sentHookKey{}, sent)
+}
+
+// cancelOnClose is a response body cancelling its context when closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and cancels the context
+func (c cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
 // sign generate Zota API signature for Deposit and Payout
 func (s SDK) sign(args ...string) (signature string) {
 
@@ -0,0 +1,130 @@
+package zota
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// SensitiveCard holds the card number (PAN) and CVV of a credit card deposit
+// the data is kept as []byte and is never exposed as a string,
+// String, GoString and MarshalJSON return the masked card data only.
+// DepositCC wipes the card before returning, whatever the outcome,
+// so a SensitiveCard can be used for a single request only
+type SensitiveCard struct {
+	number []byte
+	cvv    []byte
+}
+
+// NewSensitiveCard creates a SensitiveCard from the card number and CVV
+// the SensitiveCard takes ownership of both slices,
+// they are zeroed when the card is wiped
+func NewSensitiveCard(number []byte, cvv []byte) *SensitiveCard {
+	return &SensitiveCard{number: number, cvv: cvv}
+}
+
+// Brand returns the card brand detected from the card number
+func (c *SensitiveCard) Brand() CardBrand {
+	if c == nil {
+		return CardBrandUnknown
+	}
+	if r, ok := findCardRule(c.number); ok {
+		return r.brand
+	}
+	return CardBrandUnknown
+}
+
+// Masked returns the card number with all digits
+// except the first 6 and the last 4 replaced by '*'
+// for card numbers shorter than 13 digits only the last 4 are kept
+func (c *SensitiveCard) Masked() string {
+	if c == nil || len(c.number) == 0 {
+		return ""
+	}
+	n := len(c.number)
+	masked := bytes.Repeat([]byte("*"), n)
+	head := 6
+	if n < 13 {
+		head = 0
+	}
+	if n > 4 {
+		copy(masked[n-4:], c.number[n-4:])
+	}
+	if head > 0 {
+		copy(masked[:head], c.number[:head])
+	}
+	return string(masked)
+}
+
+// IsWiped reports whether the card data has been zeroed
+func (c *SensitiveCard) IsWiped() bool {
+	return c == nil || (c.number == nil && c.cvv == nil)
+}
+
+// Wipe zeroes the card number and CVV
+func (c *SensitiveCard) Wipe() {
+	if c == nil {
+		return
+	}
+	zero(c.number)
+	zero(c.cvv)
+	c.number = nil
+	c.cvv = nil
+}
+
+// String implements fmt.Stringer with the masked card data
+func (c *SensitiveCard) String() string {
+	if c.IsWiped() {
+		return "SensitiveCard{wiped}"
+	}
+	return fmt.Sprintf("SensitiveCard{%v %v cvv:***}", c.Brand(), c.Masked())
+}
+
+// GoString implements fmt.GoStringer with the masked card data
+func (c *SensitiveCard) GoString() string {
+	return c.String()
+}
+
+// MarshalJSON implements json.Marshaler with the masked card data
+// the full card data is written only by the DepositCC request encoder
+func (c *SensitiveCard) MarshalJSON() ([]byte, error) {
+	if c.IsWiped() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(struct {
+		Brand      CardBrand `json:"brand"`
+		CardNumber string    `json:"cardNumber"`
+		CardCvv    string    `json:"cardCvv"`
+	}{c.Brand(), c.Masked(), "***"})
+}
+
+// appendDepositCCBody builds the DepositCC request body from the json encoded order
+// and the card data, the body is allocated once with its final size
+// so no partial copies of the card data are left behind
+func (c *SensitiveCard) appendDepositCCBody(order []byte) ([]byte, error) {
+	if len(order) < 2 || order[len(order)-1] != '}' {
+		return nil, fmt.Errorf("unexpected order json")
+	}
+	const numberKey, cvvKey, end = `,"cardNumber":"`, `","cardCvv":"`, `"}`
+
+	//number and cvv are validated as digits only, no escaping is needed
+	body := make([]byte, 0, len(order)-1+len(numberKey)+len(c.number)+len(cvvKey)+len(c.cvv)+len(end))
+	body = append(body, order[:len(order)-1]...)
+	if len(order) == 2 {
+		body = append(body, numberKey[1:]...)
+	} else {
+		body = append(body, numberKey...)
+	}
+	body = append(body, c.number...)
+	body = append(body, cvvKey...)
+	body = append(body, c.cvv...)
+	body = append(body, end...)
+	return body, nil
+}
+
+// zero overwrites b with zeros
+func zero(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,195 @@
+package zota
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testPAN = "4222222222222220"
+	testCVV = "987"
+)
+
+// ClientMockCaptureCC capture the request body
+// implement httpClient interface
+type ClientMockCaptureCC struct {
+	body []byte
+	err  error
+}
+
+func (c *ClientMockCaptureCC) Do(req *http.Request) (*http.Response, error) {
+	c.body, _ = ioutil.ReadAll(req.Body)
+	r := ioutil.NopCloser(bytes.NewReader([]byte(`{
+    "code": "200",
+    "data": {
+        "status": "PROCESSING",
+        "merchantOrderID": "QvE8dZshpKhaOmHY",
+        "orderID": "8b3a6b89697e8ac8f45d964bcc90c7ba41764acd"
+    }
+}`)))
+	return &http.Response{
+		StatusCode: 200,
+		Body:       r,
+	}, c.err
+}
+
+// sensitiveOrder returns a valid DepositCCOrder using a SensitiveCard
+func sensitiveOrder() DepositCCOrder {
+	return DepositCCOrder{
+		MerchantOrderID:     "134",
+		MerchantOrderDesc:   "Test order description",
+		OrderAmount:         "500",
+		OrderCurrency:       "MYR",
+		CustomerEmail:       "customer@email-address.com",
+		CustomerLastName:    "Doe",
+		CustomerAddress:     "The Swan, Jungle St. 108",
+		CustomerCountryCode: "US",
+		CustomerCity:        "Los Angeles",
+		CustomerZipCode:     "84280",
+		CustomerPhone:       "+1 420-100-1000",
+		CustomerIP:          "127.0.0.1",
+		RedirectURL:         "https://some.endpoint/redirect",
+		CheckoutURL:         "https://some.endpoint/checkout",
+		CardHolderName:      "TEST TEST",
+		CardExpirationMonth: "01",
+		CardExpirationYear:  "2099",
+		Card:                NewSensitiveCard([]byte(testPAN), []byte(testCVV)),
+	}
+}
+
+// assertNoCardData fails if s contains the unmasked card data
+func assertNoCardData(t *testing.T, s string) {
+	assert.False(t, strings.Contains(s, testPAN), s)
+	assert.False(t, strings.Contains(s, testCVV), s)
+}
+
+func TestSensitiveCard_Masked(t *testing.T) {
+	c := NewSensitiveCard([]byte(testPAN), []byte(testCVV))
+	assert.Equal(t, "422222******2220", c.Masked())
+	assert.Equal(t, CardBrandVisa, c.Brand())
+	assert.Equal(t, "********9012", NewSensitiveCard([]byte("123456789012"), nil).Masked())
+	assert.Equal(t, "", (*SensitiveCard)(nil).Masked())
+}
+
+func TestSensitiveCard_Redacted(t *testing.T) {
+	order := sensitiveOrder()
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+		assertNoCardData(t, fmt.Sprintf(format, order))
+		assertNoCardData(t, fmt.Sprintf(format, &order))
+		assertNoCardData(t, fmt.Sprintf(format, order.Card))
+	}
+
+	b, err := json.Marshal(order)
+	assert.Equal(t, nil, err)
+	assertNoCardData(t, string(b))
+
+	b, err = json.Marshal(order.Card)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, `{"brand":"VISA","cardNumber":"422222******2220","cardCvv":"***"}`, string(b))
+}
+
+func TestSensitiveCard_Wipe(t *testing.T) {
+	number, cvv := []byte(testPAN), []byte(testCVV)
+	c := NewSensitiveCard(number, cvv)
+	c.Wipe()
+
+	assert.True(t, c.IsWiped())
+	assert.Equal(t, make([]byte, len(testPAN)), number)
+	assert.Equal(t, make([]byte, len(testCVV)), cvv)
+	assert.Equal(t, "SensitiveCard{wiped}", c.String())
+
+	b, err := json.Marshal(c)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "null", string(b))
+}
+
+func Test_DepositCCSensitiveCard(t *testing.T) {
+	client := ClientMockCaptureCC{}
+	sdk := SDK{
+		MerchantID:        "API_MERCHANT_ID",
+		MerchantSecretKey: "API_MERCHANT_SECRET_KEY",
+		EndpointID:        "503368",
+		ApiBaseURL:        SANDBOX,
+		HttpClient:        &client,
+	}
+
+	order := sensitiveOrder()
+	card := order.Card
+	res, err := sdk.DepositCC(order)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "200", res.Code)
+
+	//the card data is sent once and the card is wiped
+	var sent map[string]string
+	assert.Equal(t, nil, json.Unmarshal(client.body, &sent))
+	assert.Equal(t, testPAN, sent["cardNumber"])
+	assert.Equal(t, testCVV, sent["cardCvv"])
+	assert.Equal(t, "134", sent["merchantOrderID"])
+	assert.Equal(t, 1, strings.Count(string(client.body), testPAN))
+	assert.True(t, card.IsWiped())
+
+	//a wiped card can not be sent again
+	_, err = sdk.DepositCC(order)
+	assert.Equal(t, FieldErrors{{Field: "Card", Message: "has been wiped"}}, err)
+}
+
+func Test_DepositCCSensitiveCardMock(t *testing.T) {
+	sdk := SDK{
+		MerchantID:        "API_MERCHANT_ID",
+		MerchantSecretKey: "API_MERCHANT_SECRET_KEY",
+		EndpointID:        "503368",
+		ApiBaseURL:        SANDBOX,
+	}
+
+	//the card is wiped when the mocked response is returned
+	mock := DepositCCResult{Code: "200"}
+	mock.SetMockResponse()
+	order := sensitiveOrder()
+	res, err := sdk.DepositCC(order)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, "200", res.Code)
+	assert.True(t, order.Card.IsWiped())
+}
+
+func Test_DepositCCSensitiveCardErrors(t *testing.T) {
+	client := ClientMockCaptureCC{err: fmt.Errorf("do error")}
+	sdk := SDK{
+		MerchantID:        "API_MERCHANT_ID",
+		MerchantSecretKey: "API_MERCHANT_SECRET_KEY",
+		EndpointID:        "503368",
+		ApiBaseURL:        SANDBOX,
+		HttpClient:        &client,
+	}
+
+	//transport errors
+	order := sensitiveOrder()
+	_, err := sdk.DepositCC(order)
+	assert.Equal(t, fmt.Errorf("do error"), err)
+	assert.True(t, order.Card.IsWiped())
+
+	//validation errors
+	order = sensitiveOrder()
+	order.Card = NewSensitiveCard([]byte("4222222222222221"), []byte("98765"))
+	order.CardExpirationYear = "2000"
+	_, err = sdk.DepositCC(order)
+	assert.NotEqual(t, nil, err)
+	assertNoCardData(t, err.Error())
+	assertNoCardData(t, fmt.Sprintf("%+v", err))
+	assert.False(t, strings.Contains(err.Error(), "98765"))
+	assert.True(t, order.Card.IsWiped())
+
+	//mixed card data
+	order = sensitiveOrder()
+	order.CardNumber = testPAN
+	_, err = sdk.DepositCC(order)
+	assert.Equal(t, FieldErrors{{Field: "Card", Message: "cannot be combined with CardNumber or CardCvv"}}, err)
+	assertNoCardData(t, err.Error())
+}
@@ -0,0 +1,405 @@
+package zota
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+// WatchedOrder represents an order tracked by the StatusWatcher
+type WatchedOrder struct {
+	MerchantOrderID string          `json:"merchantOrderID"`
+	OrderID         string          `json:"orderID"`
+	Status          OrderStatusCode `json:"status"`
+	AddedAt         time.Time       `json:"addedAt"`
+	LastCheckedAt   time.Time       `json:"lastCheckedAt"`
+	NextCheckAt     time.Time       `json:"nextCheckAt"`
+	Checks          int             `json:"checks"`
+}
+
+// StatusEvent is emitted by the StatusWatcher when the status of an order changes
+// or when the order is dropped after reaching the MaxAge
+// Callback contains the order status as callback data,
+// so the event can be handled by the same code handling the callbacks
+type StatusEvent struct {
+	Order    WatchedOrder
+	Previous OrderStatusCode
+	Result   OrderStatusResult
+	Callback callback
+	Expired  bool
+}
+
+// WatcherStore persists the orders of a StatusWatcher
+// so watching resumes after a restart
+type WatcherStore interface {
+	Load() ([]WatchedOrder, error)
+	Save(orders []WatchedOrder) error
+}
+
+// FileWatcherStore is a WatcherStore keeping the orders in a json file
+type FileWatcherStore struct {
+	Path string
+}
+
+// Load implements WatcherStore, a missing file is an empty list
+func (f FileWatcherStore) Load() ([]WatchedOrder, error) {
+	b, err := os.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var orders []WatchedOrder
+	err = json.Unmarshal(b, &orders)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected watcher store json:%v", err)
+	}
+	return orders, nil
+}
+
+// Save implements WatcherStore
+// the file is replaced atomically
+func (f FileWatcherStore) Save(orders []WatchedOrder) error {
+	b, err := json.Marshal(orders)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(f.Path, b)
+}
+
+// StatusWatcherConfig configures a StatusWatcher
+// zero values are replaced by the defaults
+type StatusWatcherConfig struct {
+	// MinInterval is the poll interval of new orders, 30 seconds by default
+	MinInterval time.Duration
+	// MaxInterval is the poll interval of old orders, 30 minutes by default
+	// the interval grows with the order age: age / 10 between MinInterval and MaxInterval
+	MaxInterval time.Duration
+	// MaxAge is the age after which an order is dropped, 72 hours by default
+	MaxAge time.Duration
+	// RateLimit is the maximum number of order status requests per second, 5 by default
+	RateLimit float64
+	// Handler receives the events, if nil the events are sent to the Events channel
+	Handler func(e StatusEvent)
+	// EventsBuffer is the size of the Events channel buffer, 100 by default
+	EventsBuffer int
+	// Store persists the watched orders, optional
+	Store WatcherStore
+}
+
+// StatusWatcher polls the status of many pending orders
+// and emits an event when the status of an order changes
+// orders are dropped once they are final or older than MaxAge
+type StatusWatcher struct {
+	sdk    *SDK
+	cfg    StatusWatcherConfig
+	events chan StatusEvent
+
+	mu     sync.Mutex
+	orders map[string]*WatchedOrder
+	dirty  bool
+}
+
+// NewStatusWatcher creates a StatusWatcher
+// and loads the orders from the Store if set
+func NewStatusWatcher(s *SDK, cfg StatusWatcherConfig) (*StatusWatcher, error) {
+	if cfg.MinInterval <= 0 {
+		cfg.MinInterval = 30 * time.Second
+	}
+	if cfg.MaxInterval < cfg.MinInterval {
+		cfg.MaxInterval = 30 * time.Minute
+		if cfg.MaxInterval < cfg.MinInterval {
+			cfg.MaxInterval = cfg.MinInterval
+		}
+	}
+	if cfg.MaxAge <= 0 {
+		cfg.MaxAge = 72 * time.Hour
+	}
+	if cfg.RateLimit <= 0 {
+		cfg.RateLimit = 5
+	}
+	if cfg.EventsBuffer <= 0 {
+		cfg.EventsBuffer = 100
+	}
+
+	w := &StatusWatcher{
+		sdk:    s,
+		cfg:    cfg,
+		events: make(chan StatusEvent, cfg.EventsBuffer),
+		orders: map[string]*WatchedOrder{},
+	}
+
+	if cfg.Store != nil {
+		orders, err := cfg.Store.Load()
+		if err != nil {
+			return nil, err
+		}
+		for i := range orders {
+			o := orders[i]
+			w.orders[o.OrderID] = &o
+		}
+	}
+	return w, nil
+}
+
+// Events returns the channel receiving the events when no Handler is set
+func (w *StatusWatcher) Events() <-chan StatusEvent {
+	return w.events
+}
+
+// Watch starts tracking an order with its last known status
+// final orders are ignored, watching an order again keeps its history
+func (w *StatusWatcher) Watch(merchantOrderID string, orderID string, status OrderStatusCode) error {
+	d := OrderStatus{MerchantOrderID: merchantOrderID, OrderID: orderID}
+	err := d.validate()
+	if err != nil {
+		return err
+	}
+	if status.IsFinal() {
+		return nil
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.orders[orderID]; ok {
+		return nil
+	}
+	now := timeNow()
+	w.orders[orderID] = &WatchedOrder{
+		MerchantOrderID: merchantOrderID,
+		OrderID:         orderID,
+		Status:          status,
+		AddedAt:         now,
+		NextCheckAt:     now.Add(w.cfg.MinInterval),
+	}
+	w.dirty = true
+	return nil
+}
+
+// Unwatch stops tracking an order, e.g. when its callback has been received
+func (w *StatusWatcher) Unwatch(orderID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.orders[orderID]; ok {
+		delete(w.orders, orderID)
+		w.dirty = true
+	}
+}
+
+// Orders returns the watched orders sorted by the next check time
+func (w *StatusWatcher) Orders() []WatchedOrder {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.snapshot()
+}
+
+// snapshot returns a copy of the orders sorted by the next check time
+// w.mu must be held
+func (w *StatusWatcher) snapshot() []WatchedOrder {
+	orders := make([]WatchedOrder, 0, len(w.orders))
+	for _, o := range w.orders {
+		orders = append(orders, *o)
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		if orders[i].NextCheckAt.Equal(orders[j].NextCheckAt) {
+			return orders[i].OrderID < orders[j].OrderID
+		}
+		return orders[i].NextCheckAt.Before(orders[j].NextCheckAt)
+	})
+	return orders
+}
+
+// interval returns the poll interval of an order based on its age
+func (w *StatusWatcher) interval(age time.Duration) time.Duration {
+	i := age / 10
+	if i < w.cfg.MinInterval {
+		return w.cfg.MinInterval
+	}
+	if i > w.cfg.MaxInterval {
+		return w.cfg.MaxInterval
+	}
+	return i
+}
+
+// Run polls the due orders until ctx is done
+// the requests are spaced to respect the RateLimit
+// returns ctx.Err(), the error of the Store or the error creating the http client
+// the requests have the PriorityBatch unless ctx carries a Priority
+func (w *StatusWatcher) Run(ctx context.Context) error {
+	ctx = withDefaultPriority(ctx, PriorityBatch)
+	err := w.sdk.initHttpClient()
+	if err != nil {
+		return err
+	}
+	pace := time.Duration(float64(time.Second) / w.cfg.RateLimit)
+	tick := w.cfg.MinInterval / 2
+	if tick > time.Second {
+		tick = time.Second
+	}
+
+	for {
+		for _, o := range w.due() {
+			err := w.check(ctx, o)
+			if err != nil {
+				return err
+			}
+			if !sleep(ctx, pace) {
+				return w.stop(ctx)
+			}
+		}
+		err := w.save()
+		if err != nil {
+			return err
+		}
+		if !sleep(ctx, tick) {
+			return w.stop(ctx)
+		}
+	}
+}
+
+// stop saves the orders and returns ctx.Err()
+func (w *StatusWatcher) stop(ctx context.Context) error {
+	err := w.save()
+	if err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// due returns the orders to check now
+func (w *StatusWatcher) due() []WatchedOrder {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := timeNow()
+	var due []WatchedOrder
+	for _, o := range w.snapshot() {
+		if !o.NextCheckAt.After(now) {
+			due = append(due, o)
+		}
+	}
+	return due
+}
+
+// check polls the status of an order and emits the events
+// returns an error only if ctx is done
+func (w *StatusWatcher) check(ctx context.Context, o WatchedOrder) error {
+	res, err := w.sdk.OrderStatusContext(ctx, OrderStatus{MerchantOrderID: o.MerchantOrderID, OrderID: o.OrderID})
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	now := timeNow()
+	age := now.Sub(o.AddedAt)
+
+	w.mu.Lock()
+	current, ok := w.orders[o.OrderID]
+	if !ok {
+		//unwatched while polling
+		w.mu.Unlock()
+		return nil
+	}
+	current.LastCheckedAt = now
+	current.Checks++
+	current.NextCheckAt = now.Add(w.interval(age))
+	w.dirty = true
+
+	var events []StatusEvent
+	if err == nil && res.Code == "200" && res.Status != "" && res.Status != current.Status {
+		events = append(events, StatusEvent{Order: *current, Previous: current.Status, Result: res, Callback: res.callback()})
+		current.Status = res.Status
+		events[0].Order.Status = res.Status
+	}
+	if current.Status.IsFinal() {
+		delete(w.orders, o.OrderID)
+	} else if age > w.cfg.MaxAge {
+		delete(w.orders, o.OrderID)
+		events = append(events, StatusEvent{Order: *current, Previous: current.Status, Result: res, Expired: true})
+	}
+	w.mu.Unlock()
+
+	for _, e := range events {
+		if !w.emit(ctx, e) {
+			return ctx.Err()
+		}
+	}
+	return nil
+}
+
+// emit sends the event to the Handler or to the Events channel
+// returns false if ctx is done before the event is sent
+func (w *StatusWatcher) emit(ctx context.Context, e StatusEvent) bool {
+	if w.cfg.Handler != nil {
+		w.cfg.Handler(e)
+		return true
+	}
+	select {
+	case w.events <- e:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// save persists the orders if they changed
+func (w *StatusWatcher) save() error {
+	if w.cfg.Store == nil {
+		return nil
+	}
+	w.mu.Lock()
+	if !w.dirty {
+		w.mu.Unlock()
+		return nil
+	}
+	orders := w.snapshot()
+	w.dirty = false
+	w.mu.Unlock()
+
+	err := w.cfg.Store.Save(orders)
+	if err != nil {
+		w.mu.Lock()
+		w.dirty = true
+		w.mu.Unlock()
+	}
+	return err
+}
+
+// callback returns the order status as callback data
+func (r OrderStatusResult) callback() callback {
+	return callback{
+		Type:                   r.Type,
+		Amount:                 r.Amount,
+		Status:                 r.Status,
+		OrderID:                r.OrderID,
+		Currency:               r.Currency,
+		ExtraData:              r.ExtraData,
+		EndpointID:             r.EndpointID,
+		CustomParam:            r.CustomParam,
+		ErrorMessage:           r.ErrorMessage,
+		CustomerEmail:          r.CustomerEmail,
+		MerchantOrderID:        r.MerchantOrderID,
+		ProcessorTransactionID: r.ExternalTransactionID,
+	}
+}
+
+// sleep waits for d or until ctx is done
+// returns false if ctx is done
+func sleep(ctx context.Context, d time.Duration) bool {
+	if d <= 0 {
+		return ctx.Err() == nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
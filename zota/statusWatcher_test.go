@@ -0,0 +1,169 @@
+package zota
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ClientMockByOrder returns the mocked status sequence of the requested orderID
+// the last status is repeated
+// implement httpClient interface
+type ClientMockByOrder struct {
+	mu       sync.Mutex
+	statuses map[string][]OrderStatusCode
+	calls    map[string]int
+}
+
+func (c *ClientMockByOrder) Do(req *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	orderID := req.URL.Query().Get("orderID")
+	seq := c.statuses[orderID]
+	i := c.calls[orderID]
+	if i >= len(seq) {
+		i = len(seq) - 1
+	}
+	c.calls[orderID]++
+	body := fmt.Sprintf(`{"code":"200","data":{"status":"%v","orderID":"%v","merchantOrderID":"m-%v","amount":"10.00"}}`, seq[i], orderID, orderID)
+	return &http.Response{
+		StatusCode: 200,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(body))),
+	}, nil
+}
+
+func TestStatusWatcher_Run(t *testing.T) {
+	client := &ClientMockByOrder{
+		statuses: map[string][]OrderStatusCode{
+			"1": {StatusPending, StatusProcessing, StatusApproved},
+			"2": {StatusDeclined},
+			"3": {StatusPending},
+		},
+		calls: map[string]int{},
+	}
+
+	w, err := NewStatusWatcher(testPollSDK(client), StatusWatcherConfig{
+		MinInterval: time.Millisecond,
+		MaxInterval: 2 * time.Millisecond,
+		MaxAge:      50 * time.Millisecond,
+		RateLimit:   10000,
+	})
+	assert.Equal(t, nil, err)
+
+	assert.Equal(t, nil, w.Watch("m-1", "1", StatusPending))
+	assert.Equal(t, nil, w.Watch("m-2", "2", StatusCreated))
+	assert.Equal(t, nil, w.Watch("m-3", "3", StatusPending))
+	assert.Equal(t, nil, w.Watch("m-4", "4", StatusApproved))
+	assert.Equal(t, fmt.Errorf("OrderID is required"), w.Watch("m-5", "", StatusPending))
+	assert.Equal(t, 3, len(w.Orders()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	go w.Run(ctx)
+
+	var events []StatusEvent
+	for len(events) < 4 {
+		select {
+		case e := <-w.Events():
+			events = append(events, e)
+		case <-ctx.Done():
+			t.Fatal("timeout waiting for events")
+		}
+	}
+
+	byOrder := map[string][]string{}
+	for _, e := range events {
+		byOrder[e.Order.OrderID] = append(byOrder[e.Order.OrderID], fmt.Sprintf("%v>%v %v", e.Previous, e.Order.Status, e.Expired))
+	}
+	assert.Equal(t, []string{"PENDING>PROCESSING false", "PROCESSING>APPROVED false"}, byOrder["1"])
+	assert.Equal(t, []string{"CREATED>DECLINED false"}, byOrder["2"])
+	assert.Equal(t, []string{"PENDING>PENDING true"}, byOrder["3"])
+
+	for _, e := range events {
+		if e.Order.OrderID == "2" {
+			assert.Equal(t, StatusDeclined, e.Callback.Status)
+			assert.Equal(t, "m-2", e.Callback.MerchantOrderID)
+			assert.Equal(t, "10.00", e.Callback.Amount)
+		}
+	}
+	assert.Equal(t, 0, len(w.Orders()))
+}
+
+func TestStatusWatcher_Handler(t *testing.T) {
+	client := &ClientMockByOrder{
+		statuses: map[string][]OrderStatusCode{"1": {StatusApproved}},
+		calls:    map[string]int{},
+	}
+
+	events := make(chan StatusEvent, 1)
+	w, _ := NewStatusWatcher(testPollSDK(client), StatusWatcherConfig{
+		MinInterval: time.Millisecond,
+		Handler:     func(e StatusEvent) { events <- e },
+	})
+	w.Watch("m-1", "1", StatusPending)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.Run(ctx) }()
+
+	e := <-events
+	assert.Equal(t, StatusApproved, e.Order.Status)
+	cancel()
+	assert.Equal(t, context.Canceled, <-done)
+}
+
+func TestStatusWatcher_Store(t *testing.T) {
+	store := FileWatcherStore{Path: filepath.Join(t.TempDir(), "watcher.json")}
+	client := &ClientMockByOrder{statuses: map[string][]OrderStatusCode{"1": {StatusPending}}, calls: map[string]int{}}
+
+	w, err := NewStatusWatcher(testPollSDK(client), StatusWatcherConfig{MinInterval: time.Hour, Store: store})
+	assert.Equal(t, nil, err)
+	w.Watch("m-1", "1", StatusPending)
+	w.Watch("m-2", "2", StatusCreated)
+	w.Unwatch("2")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Equal(t, context.Canceled, w.Run(ctx))
+
+	//resume from the store
+	w2, err := NewStatusWatcher(testPollSDK(client), StatusWatcherConfig{Store: store})
+	assert.Equal(t, nil, err)
+	orders := w2.Orders()
+	assert.Equal(t, 1, len(orders))
+	assert.Equal(t, "1", orders[0].OrderID)
+	assert.Equal(t, StatusPending, orders[0].Status)
+	assert.Equal(t, w.Orders()[0].AddedAt.Unix(), orders[0].AddedAt.Unix())
+
+	//missing store file
+	orders, err = FileWatcherStore{Path: filepath.Join(t.TempDir(), "missing.json")}.Load()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, 0, len(orders))
+}
+
+func TestStatusWatcher_RunTransportError(t *testing.T) {
+	s := testPollSDK(nil)
+	s.Transport = &TransportConfig{RootCAs: []byte("not a certificate")}
+	w, err := NewStatusWatcher(s, StatusWatcherConfig{})
+	assert.Equal(t, nil, err)
+	w.Watch("m-1", "1", StatusPending)
+
+	err = w.Run(context.Background())
+	assert.Equal(t, "RootCAs contains no PEM certificate", err.Error())
+}
+
+func TestStatusWatcher_Interval(t *testing.T) {
+	w, _ := NewStatusWatcher(&SDK{}, StatusWatcherConfig{})
+	assert.Equal(t, 30*time.Second, w.interval(time.Minute))
+	assert.Equal(t, 6*time.Minute, w.interval(time.Hour))
+	assert.Equal(t, 30*time.Minute, w.interval(24*time.Hour))
+}
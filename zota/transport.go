@@ -0,0 +1,123 @@
+package zota
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// TransportConfig configures the http client created by the SDK
+// used only when SDK.HttpClient is nil, except Timeout and Timeouts
+// which apply to every request
+type TransportConfig struct {
+	// Timeout is the timeout of a request, 10 seconds by default
+	Timeout time.Duration
+	// Timeouts overrides Timeout per operation, e.g. longer for OperationOrdersReport
+	Timeouts map[Operation]time.Duration
+	// Proxy is the URL of the proxy of every request
+	Proxy *url.URL
+	// ProxyFromEnvironment uses the HTTP_PROXY, HTTPS_PROXY and NO_PROXY environment variables
+	// when Proxy is nil
+	ProxyFromEnvironment bool
+	// RootCAs are PEM encoded certificates trusted in addition to the system roots
+	RootCAs []byte
+	// MaxIdleConns, MaxIdleConnsPerHost, MaxConnsPerHost and IdleConnTimeout
+	// tune the connection pool, see http.Transport
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	MaxConnsPerHost     int
+	IdleConnTimeout     time.Duration
+	// DisableHTTP2 restricts the connections to HTTP/1.1
+	DisableHTTP2 bool
+	// PinnedPublicKeys are the base64 encoded sha256 hashes of the accepted public keys, see PublicKeyPin
+	// a connection is refused if no certificate of the verified chain has a pinned public key
+	PinnedPublicKeys []string
+}
+
+// PublicKeyPin returns the pin of the public key of a certificate
+// as expected in TransportConfig.PinnedPublicKeys
+func PublicKeyPin(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.RawSubjectPublicKeyInfo)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
+
+// timeout returns the timeout of the operation
+func (c TransportConfig) timeout(op Operation) time.Duration {
+	if d, ok := c.Timeouts[op]; ok && d > 0 {
+		return d
+	}
+	if c.Timeout > 0 {
+		return c.Timeout
+	}
+	return time.Second * 10
+}
+
+// HttpClient creates the http client of the configuration
+// the client has no timeout, the SDK applies the timeout of every operation
+func (c TransportConfig) HttpClient() (*http.Client, error) {
+	//enforce tls min version > 1.2
+	mTLSConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if len(c.RootCAs) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(c.RootCAs) {
+			return nil, fmt.Errorf("RootCAs contains no PEM certificate")
+		}
+		mTLSConfig.RootCAs = pool
+	}
+
+	if len(c.PinnedPublicKeys) > 0 {
+		pins := map[string]bool{}
+		for _, pin := range c.PinnedPublicKeys {
+			pins[pin] = true
+		}
+		mTLSConfig.VerifyConnection = func(cs tls.ConnectionState) error {
+			for _, chain := range cs.VerifiedChains {
+				for _, cert := range chain {
+					if pins[PublicKeyPin(cert)] {
+						return nil
+					}
+				}
+			}
+			return fmt.Errorf("no pinned public key in the certificate chain of %v", cs.ServerName)
+		}
+	}
+
+	tr := &http.Transport{
+		TLSClientConfig:     mTLSConfig,
+		MaxIdleConns:        c.MaxIdleConns,
+		MaxIdleConnsPerHost: c.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     c.MaxConnsPerHost,
+		IdleConnTimeout:     c.IdleConnTimeout,
+		ForceAttemptHTTP2:   !c.DisableHTTP2,
+	}
+	if c.DisableHTTP2 {
+		//a non-nil empty map disables HTTP/2
+		tr.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	switch {
+	case c.Proxy != nil:
+		tr.Proxy = http.ProxyURL(c.Proxy)
+	case c.ProxyFromEnvironment:
+		tr.Proxy = http.ProxyFromEnvironment
+	}
+
+	return &http.Client{Transport: tr}, nil
+}
+
+// timeout returns the timeout of the operation set by the SDK
+// 0 if the SDK has no TransportConfig, the timeout of the http client applies
+func (s *SDK) timeout(op Operation) time.Duration {
+	if s.Transport == nil {
+		return 0
+	}
+	return s.Transport.timeout(op)
+}
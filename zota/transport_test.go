@@ -0,0 +1,101 @@
+package zota
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ClientMockBlocking blocks every request until its context is done
+// implement httpClient interface
+type ClientMockBlocking struct{}
+
+func (c ClientMockBlocking) Do(req *http.Request) (*http.Response, error) {
+	<-req.Context().Done()
+	return nil, req.Context().Err()
+}
+
+func TestTransportConfig_Timeout(t *testing.T) {
+	assert.Equal(t, 10*time.Second, TransportConfig{}.timeout(OperationDeposit))
+
+	c := TransportConfig{Timeout: 5 * time.Second, Timeouts: map[Operation]time.Duration{OperationOrdersReport: 2 * time.Minute}}
+	assert.Equal(t, 5*time.Second, c.timeout(OperationDeposit))
+	assert.Equal(t, 2*time.Minute, c.timeout(OperationOrdersReport))
+
+	assert.Equal(t, time.Duration(0), (&SDK{}).timeout(OperationDeposit))
+	assert.Equal(t, 2*time.Minute, (&SDK{Transport: &c}).timeout(OperationOrdersReport))
+}
+
+func TestTransportConfig_HttpClient(t *testing.T) {
+	_, err := TransportConfig{RootCAs: []byte("not a certificate")}.HttpClient()
+	assert.Equal(t, "RootCAs contains no PEM certificate", err.Error())
+
+	proxy, _ := url.Parse("http://proxy.local:3128")
+	client, err := TransportConfig{Proxy: proxy, DisableHTTP2: true, MaxIdleConnsPerHost: 7}.HttpClient()
+	assert.Equal(t, nil, err)
+	assert.Equal(t, time.Duration(0), client.Timeout)
+	tr := client.Transport.(*http.Transport)
+	assert.Equal(t, 7, tr.MaxIdleConnsPerHost)
+	assert.Equal(t, false, tr.ForceAttemptHTTP2)
+	assert.NotNil(t, tr.TLSNextProto)
+	req, _ := http.NewRequest(http.MethodGet, SANDBOX, nil)
+	u, _ := tr.Proxy(req)
+	assert.Equal(t, proxy, u)
+
+	client, _ = TransportConfig{}.HttpClient()
+	tr = client.Transport.(*http.Transport)
+	assert.Equal(t, true, tr.ForceAttemptHTTP2)
+	assert.Nil(t, tr.Proxy)
+}
+
+func TestTransportConfig_TLS(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	cert := server.Certificate()
+	rootCAs := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw})
+
+	get := func(c TransportConfig) error {
+		client, err := c.HttpClient()
+		assert.Equal(t, nil, err)
+		resp, err := client.Get(server.URL)
+		if err == nil {
+			resp.Body.Close()
+		}
+		return err
+	}
+
+	assert.NotNil(t, get(TransportConfig{}))
+	assert.Equal(t, nil, get(TransportConfig{RootCAs: rootCAs}))
+	assert.Equal(t, nil, get(TransportConfig{RootCAs: rootCAs, PinnedPublicKeys: []string{"other", PublicKeyPin(cert)}}))
+	assert.Contains(t, get(TransportConfig{RootCAs: rootCAs, PinnedPublicKeys: []string{"other"}}).Error(), "no pinned public key")
+}
+
+func TestSDK_TransportTimeout(t *testing.T) {
+	sdk := testPollSDK(ClientMockBlocking{})
+	sdk.Transport = &TransportConfig{Timeouts: map[Operation]time.Duration{OperationOrderStatus: 20 * time.Millisecond}}
+	sdk.CircuitBreaker = NewCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1})
+
+	start := time.Now()
+	_, err := sdk.OrderStatus(OrderStatus{MerchantOrderID: "m-1", OrderID: "1"})
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.True(t, time.Since(start) < time.Second)
+
+	//the timeout of the operation trips the circuit breaker
+	_, err = sdk.OrderStatus(OrderStatus{MerchantOrderID: "m-1", OrderID: "1"})
+	assert.True(t, errors.Is(err, ErrCircuitOpen))
+
+	//a bad config is returned by the request
+	sdk = testPollSDK(nil)
+	sdk.Transport = &TransportConfig{RootCAs: []byte("-")}
+	_, err = sdk.OrderStatus(OrderStatus{MerchantOrderID: "m-1", OrderID: "1"})
+	assert.Equal(t, "RootCAs contains no PEM certificate", err.Error())
+}
@@ -0,0 +1,54 @@
+package zota
+
+import (
+	"sort"
+	"strings"
+)
+
+// FieldError represents a validation failure of a single order field
+// Message never contains the value of the field
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// Error implements the error interface
+func (e FieldError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// FieldErrors is the list of FieldError returned when an order fails validation
+type FieldErrors []FieldError
+
+// Error implements the error interface
+func (e FieldErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, fe := range e {
+		msgs = append(msgs, fe.Error())
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// add appends a new FieldError to the list
+func (e *FieldErrors) add(field string, message string) {
+	*e = append(*e, FieldError{Field: field, Message: message})
+}
+
+// err returns the list as an error or nil if it is empty
+func (e FieldErrors) err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// sortedKeys returns the keys of m in order
+// so validation errors are returned in a stable order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
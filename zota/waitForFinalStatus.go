@@ -0,0 +1,145 @@
+package zota
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// PollPolicy configures how WaitForFinalStatus polls the order status
+// zero values are replaced by the defaults
+type PollPolicy struct {
+	// Interval is the wait before the first retry, 2 seconds by default
+	Interval time.Duration
+	// MaxInterval caps the wait between two polls, 1 minute by default
+	MaxInterval time.Duration
+	// Backoff multiplies the interval after every poll, 1.5 by default
+	Backoff float64
+	// Jitter randomizes every wait by up to +/- Jitter of the interval (0..1)
+	Jitter float64
+	// Timeout is the overall deadline of the wait, no deadline but ctx by default
+	Timeout time.Duration
+	// MaxErrors is the number of consecutive transient errors
+	// after which the wait gives up, unlimited by default
+	MaxErrors int
+	// OnProgress is called after every poll with its result or error
+	OnProgress func(res OrderStatusResult, err error)
+}
+
+// withDefaults returns the policy with the zero values replaced by the defaults
+func (p PollPolicy) withDefaults() PollPolicy {
+	if p.Interval <= 0 {
+		p.Interval = 2 * time.Second
+	}
+	if p.MaxInterval <= 0 {
+		p.MaxInterval = time.Minute
+	}
+	if p.MaxInterval < p.Interval {
+		p.MaxInterval = p.Interval
+	}
+	if p.Backoff < 1 {
+		p.Backoff = 1.5
+	}
+	if p.Jitter < 0 {
+		p.Jitter = 0
+	}
+	if p.Jitter > 1 {
+		p.Jitter = 1
+	}
+	return p
+}
+
+// next returns the interval following current
+func (p PollPolicy) next(current time.Duration) time.Duration {
+	next := time.Duration(float64(current) * p.Backoff)
+	if next > p.MaxInterval {
+		next = p.MaxInterval
+	}
+	return next
+}
+
+// jittered returns the interval randomized by the policy Jitter
+func (p PollPolicy) jittered(interval time.Duration) time.Duration {
+	if p.Jitter == 0 {
+		return interval
+	}
+	delta := (rand.Float64()*2 - 1) * p.Jitter * float64(interval)
+	return interval + time.Duration(delta)
+}
+
+// WaitForFinalStatus polls the order status until the order reaches a final status
+// transport errors and the transient API errors (5xx, 429 and 408) are retried with the backoff,
+// other API errors are returned
+// in dry-run the synthetic result is returned right away, it never reaches a final status
+// returns the last OrderStatusResult with ctx.Err() if ctx is done
+// or the policy Timeout is reached before the order is final
+func (s *SDK) WaitForFinalStatus(ctx context.Context, merchantOrderID string, orderID string, policy PollPolicy) (res OrderStatusResult, err error) {
+
+	d := OrderStatus{MerchantOrderID: merchantOrderID, OrderID: orderID}
+
+	//validate once, validation errors are not transient
+	err = s.validate()
+	if err != nil {
+		return
+	}
+	err = d.validate()
+	if err != nil {
+		return
+	}
+
+	policy = policy.withDefaults()
+	if policy.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, policy.Timeout)
+		defer cancel()
+	}
+
+	interval := policy.Interval
+	errorsInRow := 0
+	for {
+		current, pollErr := s.OrderStatusContext(ctx, d)
+		if pollErr == nil && current.Code != "200" {
+			pollErr = fmt.Errorf("order status error code:%v message:%v", current.Code, current.Message)
+		}
+		if policy.OnProgress != nil {
+			policy.OnProgress(current, pollErr)
+		}
+
+		switch {
+		case ctx.Err() != nil:
+			return res, ctx.Err()
+		case pollErr == nil && current.DryRun:
+			return current, nil
+		case pollErr == nil:
+			res = current
+			errorsInRow = 0
+			if res.Status.IsFinal() {
+				return res, nil
+			}
+		case current.Code != "" && !transientCode(current.Code):
+			return current, pollErr
+		default:
+			errorsInRow++
+			if policy.MaxErrors > 0 && errorsInRow >= policy.MaxErrors {
+				return res, pollErr
+			}
+		}
+
+		timer := time.NewTimer(policy.jittered(interval))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return res, ctx.Err()
+		case <-timer.C:
+		}
+		interval = policy.next(interval)
+	}
+}
+
+// transientCode reports whether an API error code is retried by WaitForFinalStatus
+// server errors, rate limiting and request timeouts
+func transientCode(code string) bool {
+	return strings.HasPrefix(code, "5") || code == "429" || code == "408"
+}
@@ -0,0 +1,161 @@
+package zota
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ClientMockSequence returns the mocked responses in order
+// the last response is repeated, an empty response is returned as a Do error
+// implement httpClient interface
+type ClientMockSequence struct {
+	mu        sync.Mutex
+	responses []string
+	calls     int
+}
+
+func (c *ClientMockSequence) Do(req *http.Request) (*http.Response, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	i := c.calls
+	if i >= len(c.responses) {
+		i = len(c.responses) - 1
+	}
+	c.calls++
+	if c.responses[i] == "" {
+		return nil, fmt.Errorf("do error")
+	}
+	return &http.Response{
+		StatusCode: 200,
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(c.responses[i]))),
+	}, nil
+}
+
+// Calls returns the number of requests
+func (c *ClientMockSequence) Calls() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.calls
+}
+
+// statusResponse returns an order status response json
+func statusResponse(status OrderStatusCode) string {
+	return fmt.Sprintf(`{"code":"200","data":{"status":"%v","orderID":"1","merchantOrderID":"2"}}`, status)
+}
+
+func testPollSDK(client httpClient) *SDK {
+	return &SDK{
+		MerchantID:        "API_MERCHANT_ID",
+		MerchantSecretKey: "API_MERCHANT_SECRET_KEY",
+		EndpointID:        "503368",
+		ApiBaseURL:        SANDBOX,
+		HttpClient:        client,
+	}
+}
+
+func TestSDK_WaitForFinalStatus(t *testing.T) {
+	client := &ClientMockSequence{responses: []string{
+		statusResponse(StatusCreated),
+		"",
+		`{"code":"500","message":"internal error"}`,
+		statusResponse(StatusProcessing),
+		statusResponse(StatusApproved),
+	}}
+
+	var progress []string
+	res, err := testPollSDK(client).WaitForFinalStatus(context.Background(), "2", "1", PollPolicy{
+		Interval: time.Millisecond,
+		Jitter:   0.5,
+		OnProgress: func(res OrderStatusResult, err error) {
+			progress = append(progress, fmt.Sprintf("%v %v", res.Status, err))
+		},
+	})
+	assert.Equal(t, nil, err)
+	assert.Equal(t, StatusApproved, res.Status)
+	assert.Equal(t, 5, client.Calls())
+	assert.Equal(t, []string{
+		"CREATED <nil>",
+		" do error",
+		" order status error code:500 message:internal error",
+		"PROCESSING <nil>",
+		"APPROVED <nil>",
+	}, progress)
+}
+
+func TestSDK_WaitForFinalStatusErrors(t *testing.T) {
+	policy := PollPolicy{Interval: time.Millisecond}
+
+	//validation
+	_, err := testPollSDK(&ClientMockSequence{}).WaitForFinalStatus(context.Background(), "", "1", policy)
+	assert.Equal(t, fmt.Errorf("MerchantOrderID is required"), err)
+
+	//api errors are not retried
+	client := &ClientMockSequence{responses: []string{`{"code":"400","message":"bad request"}`}}
+	res, err := testPollSDK(client).WaitForFinalStatus(context.Background(), "2", "1", policy)
+	assert.Equal(t, fmt.Errorf("order status error code:400 message:bad request"), err)
+	assert.Equal(t, "400", res.Code)
+	assert.Equal(t, 1, client.Calls())
+
+	//rate limiting is transient
+	client = &ClientMockSequence{responses: []string{`{"code":"429","message":"too many requests"}`, statusResponse(StatusApproved)}}
+	res, err = testPollSDK(client).WaitForFinalStatus(context.Background(), "2", "1", policy)
+	assert.Equal(t, nil, err)
+	assert.Equal(t, StatusApproved, res.Status)
+	assert.Equal(t, 2, client.Calls())
+
+	//too many transient errors
+	client = &ClientMockSequence{responses: []string{statusResponse(StatusPending), ""}}
+	policy.MaxErrors = 3
+	res, err = testPollSDK(client).WaitForFinalStatus(context.Background(), "2", "1", policy)
+	assert.Equal(t, fmt.Errorf("do error"), err)
+	assert.Equal(t, StatusPending, res.Status)
+	assert.Equal(t, 4, client.Calls())
+}
+
+func TestSDK_WaitForFinalStatusDryRun(t *testing.T) {
+	//the synthetic result is not final, it is returned without polling again
+	client := &ClientMockSequence{}
+	res, err := testPollSDK(client).WaitForFinalStatus(WithDryRun(context.Background(), DryRunAll), "2", "1", PollPolicy{Interval: time.Hour})
+	assert.Equal(t, nil, err)
+	assert.True(t, res.DryRun)
+	assert.Equal(t, 0, client.Calls())
+}
+
+func TestSDK_WaitForFinalStatusDeadline(t *testing.T) {
+	client := &ClientMockSequence{responses: []string{statusResponse(StatusPending)}}
+	res, err := testPollSDK(client).WaitForFinalStatus(context.Background(), "2", "1", PollPolicy{
+		Interval: time.Millisecond,
+		Timeout:  20 * time.Millisecond,
+	})
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, StatusPending, res.Status)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = testPollSDK(client).WaitForFinalStatus(ctx, "2", "1", PollPolicy{})
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestPollPolicy(t *testing.T) {
+	p := PollPolicy{}.withDefaults()
+	assert.Equal(t, 2*time.Second, p.Interval)
+	assert.Equal(t, time.Minute, p.MaxInterval)
+	assert.Equal(t, 3*time.Second, p.next(p.Interval))
+	assert.Equal(t, time.Minute, p.next(50*time.Second))
+	assert.Equal(t, 2*time.Second, p.jittered(2*time.Second))
+
+	p = PollPolicy{Interval: time.Second, Jitter: 0.1}.withDefaults()
+	for i := 0; i < 100; i++ {
+		j := p.jittered(time.Second)
+		assert.True(t, j >= 900*time.Millisecond && j <= 1100*time.Millisecond, j)
+	}
+}